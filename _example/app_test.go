@@ -3,7 +3,10 @@ package eudore_test
 import (
 	"context"
 	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	. "github.com/eudore/eudore"
 	. "github.com/eudore/eudore/middleware"
@@ -73,3 +76,185 @@ func TestAppListen(*testing.T) {
 	app.CancelFunc()
 	app.Run()
 }
+
+func TestAppLifecycleLogger(t *testing.T) {
+	ring := NewLoggerWriterRing(20)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+	app.SetValue(ContextKeyClient, NewClient())
+	app.Listen("127.0.0.1:0")
+
+	app.CancelFunc()
+	app.Run()
+
+	lines := strings.Join(ring.(interface{ Lines() []string }).Lines(), "\n")
+	for _, msg := range []string{
+		"eudore app component client mounted",
+		"eudore app component server unmounted",
+		"listen http in tcp 127.0.0.1:",
+		"eudore app shutting down, draining 0 requests",
+	} {
+		if !strings.Contains(lines, msg) {
+			t.Errorf("not found lifecycle log %q: %s", msg, lines)
+		}
+	}
+}
+
+func TestAppRecover(t *testing.T) {
+	app := NewApp()
+	messages := make(chan string, 4)
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Stdout: true,
+		Handlers: []LoggerHandler{NewLoggerHookFatal(func(entry *LoggerEntry) {
+			if len(entry.Keys) > 0 && entry.Keys[0] == "stack" {
+				messages <- entry.Message
+			}
+		})},
+	}))
+
+	app.Go(func(context.Context) {
+		panic("background panic")
+	}, true)
+	if msg := <-messages; msg != "background panic" {
+		t.Error("recover message:", msg)
+	}
+
+	app.ParseOption(func(context.Context, Config) error {
+		panic(errors.New("parse panic"))
+	})
+	err := app.Parse()
+	if err == nil || err.Error() != "parse panic" {
+		t.Error("parse error:", err)
+	}
+	if msg := <-messages; msg != "parse panic" {
+		t.Error("recover message:", msg)
+	}
+
+	app.Run()
+}
+
+func TestAppGo(t *testing.T) {
+	ring := NewLoggerWriterRing(20)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+
+	var done int32
+	started := make(chan struct{})
+	app.Go(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 20)
+		atomic.StoreInt32(&done, 1)
+	})
+	app.Go(func(context.Context) {
+		panic("task panic")
+	})
+	<-started
+
+	app.CancelFunc()
+	err := app.Run()
+	if !errors.Is(err, context.Canceled) {
+		t.Error("app error:", err)
+	}
+	if atomic.LoadInt32(&done) != 1 {
+		t.Error("task is not waited on shutdown")
+	}
+	lines := strings.Join(ring.(interface{ Lines() []string }).Lines(), "\n")
+	if !strings.Contains(lines, "task panic") || !strings.Contains(lines, "stack") {
+		t.Error("task panic not logged:", lines)
+	}
+}
+
+func TestAppSchedule(t *testing.T) {
+	ring := NewLoggerWriterRing(100)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+
+	var count int32
+	runs := make(chan struct{}, 10)
+	err := app.Schedule("@every 10ms", func(context.Context) {
+		if atomic.AddInt32(&count, 1) == 2 {
+			panic("schedule panic")
+		}
+		runs <- struct{}{}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	for _, spec := range []string{"*/5 * * * *", "0 9-18/3 * * 1-5", "30s"} {
+		if err := app.Schedule(spec, func(context.Context) {}); err != nil {
+			t.Error(err)
+		}
+	}
+	for _, spec := range []string{"", "-1s", "* * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *"} {
+		if app.Schedule(spec, func(context.Context) {}) == nil {
+			t.Errorf("schedule spec %q must be invalid", spec)
+		}
+	}
+	<-runs
+	<-runs
+
+	app.CancelFunc()
+	app.Run()
+	stopped := atomic.LoadInt32(&count)
+	time.Sleep(time.Millisecond * 30)
+	if stopped < 3 || atomic.LoadInt32(&count) != stopped {
+		t.Error("schedule runs after shutdown:", stopped, atomic.LoadInt32(&count))
+	}
+	lines := strings.Join(ring.(interface{ Lines() []string }).Lines(), "\n")
+	for _, msg := range []string{"eudore app schedule done", "schedule panic"} {
+		if !strings.Contains(lines, msg) {
+			t.Errorf("not found schedule log %q: %s", msg, lines)
+		}
+	}
+}
+
+func TestAppSlowHandler(t *testing.T) {
+	defer func(d time.Duration) { DefaultContextSlowHandler = d }(DefaultContextSlowHandler)
+	DefaultContextSlowHandler = time.Millisecond * 20
+
+	ring := NewLoggerWriterRing(20)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+	lines := func() string {
+		return strings.Join(ring.(interface{ Lines() []string }).Lines(), "\n")
+	}
+	app.GetFunc("/hang", func(ctx Context) {
+		for i := 0; i < 100; i++ {
+			if strings.Contains(lines(), ErrAppSlowHandler.Error()) {
+				ctx.WriteString("warned")
+				return
+			}
+			time.Sleep(time.Millisecond * 5)
+		}
+	})
+	app.GetFunc("/fast", HandlerEmpty)
+
+	err := app.GetRequest("/hang",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("warned"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.Contains(lines(), `"path":"/hang"`) {
+		t.Error("slow handler log:", lines())
+	}
+
+	app.GetRequest("/fast")
+	time.Sleep(time.Millisecond * 40)
+	if strings.Contains(lines(), `"path":"/fast"`) {
+		t.Error("fast handler must not warn:", lines())
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
@@ -0,0 +1,50 @@
+package eudore_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/eudore/eudore"
+)
+
+type benchBindData struct {
+	Name  string   `json:"name"`
+	Age   int      `json:"age"`
+	Email string   `json:"email"`
+	Tags  []string `json:"tags"`
+}
+
+var benchBindBody = `{"name":"eudore","age":18,"email":"eudore@example.com",` +
+	`"tags":["` + strings.Repeat("tag", 256) + `"]}`
+
+func BenchmarkBindJSONPool(b *testing.B) {
+	benchmarkBindJSON(b, DefaultHandlerDataBindBufferSize)
+}
+
+func BenchmarkBindJSONNoPool(b *testing.B) {
+	benchmarkBindJSON(b, 0)
+}
+
+func benchmarkBindJSON(b *testing.B, size int) {
+	defer func(size int) {
+		DefaultHandlerDataBindBufferSize = size
+	}(DefaultHandlerDataBindBufferSize)
+	DefaultHandlerDataBindBufferSize = size
+
+	app := NewApp()
+	ctx := app.ContextPool.Get().(Context)
+	w := httptest.NewRecorder()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(benchBindBody))
+		r.Header.Set(HeaderContentType, MimeApplicationJSON)
+		ctx.Reset(w, r)
+		var data benchBindData
+		err := ctx.Bind(&data)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package eudore_test
+
+/*
+goos: linux
+goarch: amd64
+cpu: Intel(R) Xeon(R) Processor
+BenchmarkLoggerCallerCache   	  685173	      2025 ns/op	     304 B/op	       7 allocs/op
+BenchmarkLoggerCallerNoCache 	  442632	      2755 ns/op	     624 B/op	      10 allocs/op
+PASS
+ok  	command-line-arguments	2.870s
+*/
+
+import (
+	"testing"
+
+	. "github.com/eudore/eudore"
+)
+
+func BenchmarkLoggerCallerCache(b *testing.B) {
+	benchmarkLoggerCaller(b, true)
+}
+
+func BenchmarkLoggerCallerNoCache(b *testing.B) {
+	benchmarkLoggerCaller(b, false)
+}
+
+func benchmarkLoggerCaller(b *testing.B, cache bool) {
+	defer func(cache bool) {
+		DefaultLoggerCallerCache = cache
+	}(DefaultLoggerCallerCache)
+	DefaultLoggerCallerCache = cache
+
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{NewLoggerWriterRing(1)},
+		Caller:   true,
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("caller")
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
+	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/eudore/eudore"
 )
@@ -34,6 +38,68 @@ func TestConfigStdGetSet(t *testing.T) {
 	c.(interface{ Metadata() any }).Metadata()
 }
 
+func TestConfigHandler(t *testing.T) {
+	type database struct {
+		Host     string `alias:"host"`
+		Password string `alias:"password" secret:"true"`
+	}
+	type config struct {
+		Name     string            `alias:"name"`
+		Token    string            `alias:"token" secret:"true"`
+		Database *database         `alias:"database"`
+		Labels   map[string]string `alias:"labels"`
+		Handler  func()            `alias:"-"`
+		Timeout  time.Duration     `alias:"timeout"`
+		Start    time.Time         `alias:"start"`
+		Level    configLevel       `alias:"level"`
+	}
+	conf := &config{
+		Name:     "eudore",
+		Token:    "token-value",
+		Database: &database{Host: "localhost", Password: "db-password"},
+		Labels:   map[string]string{"env": "dev"},
+		Timeout:  time.Second,
+		Level:    configLevel{2},
+	}
+
+	app := NewApp()
+	app.GetFunc("/config", NewHandlerConfig(NewConfig(conf)))
+	err := app.GetRequest("/config",
+		NewClientHeader(HeaderAccept, MimeApplicationJSON),
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`"name":"eudore"`),
+		NewClientCheckBody(`"token":"******"`),
+		NewClientCheckBody(`"host":"localhost"`),
+		NewClientCheckBody(`"password":"******"`),
+		NewClientCheckBody(`"labels":{"env":"dev"}`),
+		NewClientCheckBody(`"timeout":1000000000`),
+		NewClientCheckBody(`"level":"level-2"`),
+		func(w *http.Response) error {
+			body, _ := io.ReadAll(w.Body)
+			if strings.Contains(string(body), "token-value") ||
+				strings.Contains(string(body), "db-password") ||
+				strings.Contains(string(body), "Handler") {
+				return fmt.Errorf("config not redacted: %s", body)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+type configLevel struct {
+	Level int
+}
+
+func (l *configLevel) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("level-%d", l.Level)), nil
+}
+
 func TestConfigStdpParse(t *testing.T) {
 	c := NewConfig(nil)
 	c.ParseOption(func(ctx context.Context, config Config) error {
@@ -148,16 +214,144 @@ func TestConfigParseArgsShort(t *testing.T) {
 		Config string `alias:"config" json:"config" flag:"c"`
 		Name   string `alias:"name" json:"name"`
 	}
-	os.Args = append(os.Args, "--name=eudore", "-f=config.json", "-h", "--help")
+	os.Args = append(os.Args, "--name=eudore", "-f=config.json", "-c", "app.json", "-h")
+	defer func() {
+		os.Args = os.Args[:len(os.Args)-5]
+	}()
 
-	c := NewConfig(&configShort{false, "eudore", "msg"})
+	conf := &configShort{false, "eudore", "msg"}
+	c := NewConfig(conf)
 	c.ParseOption()
 	c.ParseOption(NewConfigParseArgs())
 
-	t.Logf("NewConfigParseArgs parse error: %v", c.Parse(context.Background()))
+	err := c.Parse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NewConfigParseArgs not consume the next argument.
+	if conf.Config != "" || !conf.Help {
+		t.Fatalf("parse args short error: %#v", conf)
+	}
 	t.Logf("Config data: %# v", c.Get(""))
 }
 
+func TestConfigParseFlags(t *testing.T) {
+	type configServer struct {
+		Addr  string `alias:"addr"`
+		Port  int    `alias:"port" flag:"p"`
+		Debug bool   `alias:"debug"`
+	}
+	type configFlags struct {
+		Name   string        `alias:"name"`
+		Hosts  []string      `alias:"hosts"`
+		Server *configServer `alias:"server"`
+		Args   []string      `alias:"args"`
+		Offset int           `alias:"offset"`
+	}
+	conf := &configFlags{Server: &configServer{}}
+	c := NewConfig(conf)
+	c.ParseOption()
+	c.ParseOption(NewConfigParseFlags([]string{
+		"--name", "eudore", "--server.port=8080",
+		"--server.debug", "--server.addr", "localhost",
+		"--hosts=a", "--hosts", "b", "start", "--", "--name=stop", "--help",
+	}))
+	err := c.Parse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Name != "eudore" || conf.Server.Port != 8080 ||
+		conf.Server.Addr != "localhost" || !conf.Server.Debug {
+		t.Fatalf("parse flags error: %#v %#v", conf, conf.Server)
+	}
+	if strings.Join(conf.Hosts, ",") != "a,b" {
+		t.Fatalf("parse repeated flags error: %v", conf.Hosts)
+	}
+	if strings.Join(conf.Args, " ") != "start --name=stop --help" {
+		t.Fatalf("parse positional args error: %v", conf.Args)
+	}
+
+	c.ParseOption()
+	c.ParseOption(NewConfigParseFlags([]string{"-p", "80", "--offset", "-5"}))
+	err = c.Parse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Server.Port != 80 || conf.Offset != -5 {
+		t.Fatalf("parse short flags error: %d %d", conf.Server.Port, conf.Offset)
+	}
+
+	// capture the usage of --help written to os.Stdout.
+	c.ParseOption()
+	c.ParseOption(NewConfigParseFlags([]string{"--help", "--name", "help"}))
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	err = c.Parse(context.Background())
+	os.Stdout = stdout
+	w.Close()
+	usage, _ := io.ReadAll(r)
+	r.Close()
+	if !errors.Is(err, flag.ErrHelp) || conf.Name != "eudore" {
+		t.Fatalf("parse help error: %v %s", err, conf.Name)
+	}
+	if c.Get("help") != nil {
+		t.Fatalf("parse help set key: %v", c.Get("help"))
+	}
+	for _, key := range []string{"Usage of", "--name", "--server.port"} {
+		if !strings.Contains(string(usage), key) {
+			t.Errorf("usage not has %s: %s", key, usage)
+		}
+	}
+}
+
+func TestConfigParseArgsNotConsume(t *testing.T) {
+	os.Args = append(os.Args, "--debug", "run.txt", "--name", "eudore")
+	defer func() {
+		os.Args = os.Args[:len(os.Args)-4]
+	}()
+
+	conf := map[string]any{}
+	c := NewConfig(conf)
+	c.ParseOption()
+	c.ParseOption(NewConfigParseArgs())
+	err := c.Parse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf["debug"] != "" || conf["name"] != "" {
+		t.Fatalf("parse args consume value: %v", conf)
+	}
+	args, _ := conf["args"].([]string)
+	if strings.Join(args[len(args)-2:], " ") != "run.txt eudore" {
+		t.Fatalf("parse args positional error: %v", conf["args"])
+	}
+}
+
+func TestConfigUsage(t *testing.T) {
+	type configUsage struct {
+		Name   string        `alias:"name" description:"app name"`
+		Logger *LoggerConfig `alias:"logger"`
+	}
+	usage := GetConfigUsage(&configUsage{
+		Name:   "eudore",
+		Logger: &LoggerConfig{Path: "app.log"},
+	})
+	for _, str := range []string{
+		"--name", "app name", "eudore",
+		"--logger.path", "log file path", "app.log",
+		"--logger.asyncTimeout", "time.Duration",
+	} {
+		if !strings.Contains(usage, str) {
+			t.Fatalf("usage not contains %s:\n%s", str, usage)
+		}
+	}
+	t.Log(usage)
+}
+
 func TestConfigParseEnvs(t *testing.T) {
 	os.Setenv("ENV_NAME", "eudore")
 	defer os.Unsetenv("ENV_NAME")
@@ -170,6 +364,37 @@ func TestConfigParseEnvs(t *testing.T) {
 	t.Logf("Config data: %# v", c.Get(""))
 }
 
+func TestConfigSetBoolLiterals(t *testing.T) {
+	type config struct {
+		Debug bool `alias:"debug"`
+	}
+	conf := &config{}
+	c := NewConfig(conf)
+	for val, want := range map[string]bool{
+		"yes": true, "no": false, "ON": true, "Off": false,
+		"1": true, "false": false,
+	} {
+		conf.Debug = !want
+		err := c.Set("debug", val)
+		if err != nil || conf.Debug != want {
+			t.Errorf("set bool %s error: %v %v", val, conf.Debug, err)
+		}
+	}
+	if c.Set("debug", "enable") == nil {
+		t.Error("set bool enable must error")
+	}
+	if !GetAnyByString[bool]("Yes") {
+		t.Error("GetAnyByString bool yes is false")
+	}
+
+	literals := DefaultValueParseBoolLiterals
+	defer func() { DefaultValueParseBoolLiterals = literals }()
+	DefaultValueParseBoolLiterals = nil
+	if c.Set("debug", "yes") == nil {
+		t.Error("set bool yes must error without literals")
+	}
+}
+
 func TestConfigParseEnvsFile(t *testing.T) {
 	defer tempConfigFile(".env", "A=2\r\nB=\r\nC='2\r\n2\r\n2'\r\nD='2\r\n2\r\n2'2\r\n")()
 	defer os.Unsetenv("ENV_NAME")
@@ -200,3 +425,55 @@ func TestConfigParseWorkdir(t *testing.T) {
 	t.Logf("NewConfigParseWorkdir parse error: %v", c.Parse(context.Background()))
 	t.Logf("Config data: %# v", c.Get(""))
 }
+
+func TestConfigParseExpand(t *testing.T) {
+	type Config struct {
+		Path  string            `alias:"path"`
+		Addr  string            `alias:"addr"`
+		Other string            `alias:"other"`
+		Price string            `alias:"price"`
+		Hosts []string          `alias:"hosts"`
+		Env   map[string]string `alias:"env"`
+		Any   map[string]any    `alias:"any"`
+	}
+	os.Setenv("EUDORE_EXPAND_HOME", "/home/eudore")
+	defer os.Unsetenv("EUDORE_EXPAND_HOME")
+
+	data := &Config{
+		Path:  "${EUDORE_EXPAND_HOME}/data",
+		Addr:  ":$EUDORE_EXPAND_PORT",
+		Other: "${EUDORE_EXPAND_NONE}/$EUDORE_EXPAND_NONE",
+		Price: "$$10 $",
+		Hosts: []string{"$EUDORE_EXPAND_HOME"},
+		Env:   map[string]string{"home": "${EUDORE_EXPAND_HOME}"},
+		Any:   map[string]any{"home": "${EUDORE_EXPAND_HOME}", "num": 1},
+	}
+	lookup := func(key string) (string, bool) {
+		if key == "EUDORE_EXPAND_PORT" {
+			return "8088", true
+		}
+		return os.LookupEnv(key)
+	}
+	conf := NewConfig(data)
+	conf.ParseOption()
+	conf.ParseOption(NewConfigParseExpand(lookup, false))
+	err := conf.Parse(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Path != "/home/eudore/data" || data.Addr != ":8088" ||
+		data.Other != "${EUDORE_EXPAND_NONE}/$EUDORE_EXPAND_NONE" ||
+		data.Price != "$10 $" || data.Hosts[0] != "/home/eudore" ||
+		data.Env["home"] != "/home/eudore" || data.Any["home"] != "/home/eudore" ||
+		data.Any["num"] != 1 {
+		t.Fatalf("expand error: %#v", data)
+	}
+
+	conf = NewConfig(map[string]any{"path": "${EUDORE_EXPAND_NONE}/data"})
+	conf.ParseOption()
+	conf.ParseOption(NewConfigParseExpand(nil, true))
+	err = conf.Parse(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "EUDORE_EXPAND_NONE") {
+		t.Fatal("expand strict error:", err)
+	}
+}
@@ -4,14 +4,25 @@ import (
 	"bufio"
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	. "github.com/eudore/eudore"
+	. "github.com/eudore/eudore/middleware"
 )
 
 func TestContextRequest(*testing.T) {
@@ -478,3 +489,1112 @@ func TestContextValues(*testing.T) {
 	app.CancelFunc()
 	app.Run()
 }
+
+func TestContextValueStash(t *testing.T) {
+	type user struct{ Name string }
+	keyUser := NewContextKey("user")
+	keyRequestID := NewContextKey("request-id")
+
+	app := NewApp()
+	app.AddMiddleware(func(ctx Context) {
+		if ctx.GetQuery("set") != "" {
+			ctx.SetValue(keyUser, &user{Name: "eudore"})
+			ctx.SetValue(keyRequestID, ctx.GetQuery("set"))
+		}
+	})
+	app.GetFunc("/", func(ctx Context) {
+		name := "none"
+		u := GetContextValue[*user](ctx, keyUser)
+		if u != nil {
+			name = u.Name
+		}
+		ctx.WriteString(name + " " + GetContextValue[string](ctx, keyRequestID))
+	})
+
+	for _, req := range [][2]string{
+		{"/?set=1", "eudore 1"},
+		{"/", "none "},
+		{"/?set=2", "eudore 2"},
+	} {
+		err := app.GetRequest(req[0], NewClientCheckBody(req[1]))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+	if GetContextValue[int](app, keyUser) != 0 {
+		t.Fatal("app value must be empty")
+	}
+}
+
+func TestContextLoggerInherit(t *testing.T) {
+	ring := NewLoggerWriterRing(20)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.AddMiddleware(func(ctx Context) {
+		ctx.SetValue(ContextKeyLogger, ctx.WithField("userid", 1))
+	}, func(ctx Context) {
+		ctx.SetValue(ContextKeyLogger, ctx.WithField("role", "admin"))
+	})
+	helper := func(ctx Context) {
+		ctx.Info("helper")
+	}
+	app.GetFunc("/inherit", func(ctx Context) {
+		ctx.Info("handler")
+		ctx.WithField("temp", true).Info("temp")
+		helper(ctx)
+		ctx.Logger().Info("logger")
+	})
+
+	for i := 0; i < 2; i++ {
+		app.GetRequest("/inherit")
+	}
+
+	var num int
+	for _, line := range ring.(interface{ Lines() []string }).Lines() {
+		if !strings.Contains(line, `"userid":1`) {
+			continue
+		}
+		num++
+		if strings.Count(line, "\n") > 0 ||
+			strings.Count(line, `"userid"`) != 1 ||
+			!strings.Contains(line, `"role":"admin"`) ||
+			strings.Contains(line, `"temp":true`) !=
+				strings.Contains(line, `"message":"temp"`) {
+			t.Errorf("invalid inherit log: %s", line)
+		}
+	}
+	if num != 8 {
+		t.Errorf("inherit logs %d", num)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextPoolReset(t *testing.T) {
+	key := NewContextKey("stash")
+	app := NewApp()
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/set", func(ctx Context) {
+		ctx.SetParam("secret", "eudore")
+		ctx.SetValue(key, "value")
+		ctx.SetValue(ContextKeyError, fmt.Errorf("test error"))
+		ctx.SetValue(ContextKeyLogger, ctx.WithField("secret", "eudore"))
+		ctx.Body()
+		ctx.Cookies()
+	})
+	app.GetFunc("/get", func(ctx Context) {
+		body, _ := ctx.Body()
+		fmt.Fprintf(ctx, "params=%s value=%v err=%v cookies=%d body=%d",
+			ctx.Params().String(), ctx.Value(key), ctx.Err(),
+			len(ctx.Cookies()), len(body),
+		)
+	})
+
+	for i := 0; i < 3; i++ {
+		app.PostRequest("/set", strings.NewReader("body"),
+			NewClientHeader(HeaderCookie, "name=eudore"),
+		)
+		err := app.GetRequest("/get", NewClientCheckBody(
+			"params=route=/get value=<nil> err=<nil> cookies=0 body=0",
+		))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextRedirectSafe(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/api/v1/redirect", func(ctx Context) error {
+		return ctx.Redirect(302, "users")
+	})
+	app.GetFunc("/invalid", func(ctx Context) error {
+		return ctx.Redirect(200, "/")
+	})
+	app.GetFunc("/safe", func(ctx Context) error {
+		return ctx.RedirectSafe(302, ctx.GetQuery("to"), "eudore.cn")
+	})
+
+	reqs := []struct {
+		path     string
+		status   int
+		location string
+	}{
+		{"/api/v1/redirect", 302, "/api/v1/users"},
+		{"/invalid", 500, ""},
+		{"/safe?to=/index", 302, "/index"},
+		{"/safe?to=https://eudore.cn/", 302, "https://eudore.cn/"},
+		{"/safe?to=http://" + DefaultClientInternalHost + "/", 302, "http://" + DefaultClientInternalHost + "/"},
+		{"/safe?to=https://example.com/", 500, ""},
+		{"/safe?to=//example.com/", 500, ""},
+		{"/safe?to=/\\example.com/", 500, ""},
+		{"/safe?to=\\\\example.com/", 500, ""},
+		{"/safe?to=https:example.com/", 500, ""},
+		{"/safe?to=http:/example.com/", 500, ""},
+		{"/safe?to=javascript:alert(1)", 500, ""},
+		{"/safe?to=%20//example.com/", 500, ""},
+		{"/safe?to=/%09/example.com/", 500, ""},
+		{"/safe?to=https://eudore.cn@example.com/", 500, ""},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			NewClientHookRedirect(func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}),
+			NewClientCheckStatus(r.status),
+			func(w *http.Response) error {
+				if w.Header.Get(HeaderLocation) != r.location {
+					return fmt.Errorf("location is %s", w.Header.Get(HeaderLocation))
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextAttachment(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/attachment", func(ctx Context) {
+		ctx.Attachment(ctx.GetQuery("name"))
+		ctx.WriteString("data")
+	})
+	app.GetFunc("/static/* autoindex=true attachment=true", NewHandlerFileSystems("."))
+
+	reqs := []struct {
+		path        string
+		disposition string
+	}{
+		{"/attachment?name=report.csv", `attachment; filename="report.csv"`},
+		{"/attachment?name=a%22b.txt", `attachment; filename="a\"b.txt"`},
+		{"/attachment?name=%E6%8A%A5%E5%91%8A.csv", `attachment; filename="__.csv"; filename*=UTF-8''%E6%8A%A5%E5%91%8A.csv`},
+		{"/attachment?name=r%C3%A9sum%C3%A9+1.pdf", `attachment; filename="r_sum_ 1.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%201.pdf`},
+		{"/static/context_test.go", `attachment; filename="context_test.go"`},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			NewClientCheckStatus(200),
+			func(w *http.Response) error {
+				if w.Header.Get(HeaderContentDisposition) != r.disposition {
+					return fmt.Errorf("disposition is %s", w.Header.Get(HeaderContentDisposition))
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextMultipart(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/multipart", func(ctx Context) error {
+		w := ctx.Multipart()
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			HeaderContentType: {MimeApplicationJSON},
+		})
+		if err != nil {
+			return err
+		}
+		part.Write([]byte(`{"name":"eudore"}`))
+		part, err = w.CreatePart(textproto.MIMEHeader{
+			HeaderContentType:        {MimeTextPlain},
+			HeaderContentDisposition: {`attachment; filename="doc.txt"`},
+		})
+		if err != nil {
+			return err
+		}
+		part.Write([]byte("document"))
+		return w.Close()
+	})
+
+	err := app.GetRequest("/multipart",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			mediatype, params, err := mime.ParseMediaType(w.Header.Get(HeaderContentType))
+			if err != nil || mediatype != MimeMultipartMixed {
+				return fmt.Errorf("invalid content type %s", w.Header.Get(HeaderContentType))
+			}
+			reader := multipart.NewReader(w.Body, params["boundary"])
+			var parts []string
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+				body, _ := io.ReadAll(part)
+				parts = append(parts, part.Header.Get(HeaderContentType)+" "+string(body))
+			}
+			if len(parts) != 2 ||
+				parts[0] != MimeApplicationJSON+` {"name":"eudore"}` ||
+				parts[1] != MimeTextPlain+" document" {
+				return fmt.Errorf("invalid parts %q", parts)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextContext(t *testing.T) {
+	wait := func(c context.Context) error {
+		select {
+		case <-c.Done():
+			return c.Err()
+		default:
+			return nil
+		}
+	}
+
+	app := NewApp()
+	app.GetFunc("/ctx", func(ctx Context) {
+		if ctx.GetQuery("cancel") != "" {
+			c, cancel := context.WithCancel(ctx.Request().Context())
+			cancel()
+			ctx.SetContext(c)
+		}
+		if ctx.GetQuery("timeout") != "" {
+			c, cancel := context.WithTimeout(ctx, time.Hour)
+			defer cancel()
+			_, ok := c.Deadline()
+			ctx.WriteString(fmt.Sprint(ok, " "))
+		}
+		ctx.WriteString(fmt.Sprint(wait(ctx)))
+	})
+
+	reqs := []struct {
+		path string
+		body string
+	}{
+		{"/ctx", "<nil>"},
+		{"/ctx?cancel=1", "context canceled"},
+		{"/ctx?timeout=1", "true <nil>"},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path, NewClientCheckStatus(200), NewClientCheckBody(r.body))
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextContentType(t *testing.T) {
+	dir := t.TempDir()
+	css := filepath.Join(dir, "style.css")
+	_ = os.WriteFile(css, []byte("body{}"), 0o644)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 2048)...)
+
+	app := NewApp()
+	app.AddMiddleware(NewGzipFunc())
+	app.GetFunc("/css", func(ctx Context) error {
+		return ctx.WriteFile(css)
+	})
+	app.GetFunc("/png", func(ctx Context) {
+		ctx.Write(png)
+	})
+	app.GetFunc("/html", func(ctx Context) {
+		ctx.WriteString("<html><body>" + strings.Repeat("eudore", 200))
+	})
+	app.GetFunc("/set", func(ctx Context) {
+		ctx.SetHeader(HeaderContentType, MimeTextPlain)
+		ctx.Write(png)
+	})
+
+	reqs := []struct {
+		path string
+		mime string
+	}{
+		{"/css", "text/css"},
+		{"/png", "image/png"},
+		{"/html", MimeTextHTMLCharsetUtf8},
+		{"/set", MimeTextPlain},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			http.Header{HeaderAcceptEncoding: {"gzip"}},
+			NewClientCheckStatus(200),
+			func(w *http.Response) error {
+				if !strings.HasPrefix(w.Header.Get(HeaderContentType), r.mime) {
+					return fmt.Errorf("content-type %s not %s",
+						w.Header.Get(HeaderContentType), r.mime,
+					)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextSetParam(t *testing.T) {
+	mapfs := fstest.MapFS{
+		"eudore/index.html": {Data: []byte("eudore index")},
+	}
+
+	app := NewApp()
+	app.AddMiddleware(func(ctx Context) {
+		tenant := ctx.GetHeader("X-Tenant")
+		if tenant != "" {
+			ctx.SetParam("tenant", tenant)
+			ctx.SetParam(ParamPrefix, tenant)
+		}
+	})
+	app.GetFunc("/tenant", func(ctx Context) {
+		ctx.WriteString("tenant:" + ctx.GetParam("tenant"))
+	})
+	app.GetFunc("/static/*", mapfs)
+
+	reqs := []struct {
+		path   string
+		tenant string
+		status int
+		body   string
+	}{
+		{"/tenant", "eudore", 200, "tenant:eudore"},
+		{"/tenant", "", 200, "tenant:"},
+		{"/static/index.html", "eudore", 200, "eudore index"},
+		{"/static/index.html", "", 404, ""},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			http.Header{"X-Tenant": {r.tenant}},
+			NewClientCheckStatus(r.status), NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, r.tenant, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextPagination(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/list", func(ctx Context) any {
+		return ctx.Pagination(Pagination{
+			Size: 10, MaxSize: 50,
+			Sort: []PaginationSort{{Field: "id"}},
+		})
+	})
+	app.GetFunc("/default", func(ctx Context) any {
+		return ctx.Pagination(Pagination{Size: 500})
+	})
+
+	reqs := []struct {
+		path string
+		body string
+	}{
+		{"/list", `{"page":1,"size":10,"sort":[{"field":"id","desc":false}]}`},
+		{"/list?page=3&size=20", `{"page":3,"size":20,"sort":[{"field":"id","desc":false}]}`},
+		{"/list?page=-1&size=abc", `{"page":1,"size":10,`},
+		{"/list?size=1000", `{"page":1,"size":50,`},
+		{"/list?sort=-created,name:asc,+age,score:desc", `"sort":[{"field":"created","desc":true},{"field":"name","desc":false},{"field":"age","desc":false},{"field":"score","desc":true}]`},
+		{"/list?sort=name;drop,user.name:up,,-", `"sort":[{"field":"id","desc":false}]`},
+		{"/default", `{"page":1,"size":100}`},
+		{"/default?size=30", `{"page":1,"size":30}`},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			http.Header{HeaderAccept: {MimeApplicationJSON}},
+			NewClientCheckStatus(200), NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextFormValue(t *testing.T) {
+	app := NewApp()
+	app.PostFunc("/form", func(ctx Context) {
+		fmt.Fprintf(ctx, "%s %s %d %d %q",
+			ctx.FormValueDefault("name", "guest"),
+			ctx.FormValueDefault("none", "guest"),
+			ctx.FormValueInt("age"),
+			ctx.FormValueInt("size", 20),
+			ctx.FormValueList("tag"),
+		)
+	})
+
+	form := url.Values{"name": {"eudore"}, "age": {"12"}, "size": {"abc"}, "tag": {"a", "b"}}
+	multipart := NewClientBodyForm(form)
+	multipart.AddFile("file", "app.txt", []byte("app"))
+	bodys := []ClientBody{NewClientBodyForm(form), multipart}
+	for _, body := range bodys {
+		err := app.PostRequest("/form", body,
+			NewClientCheckStatus(200),
+			NewClientCheckBody(`eudore guest 12 20 ["a" "b"]`),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	err := app.PostRequest("/form", NewClientBodyJSON(form),
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`guest guest 0 20 []`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextBindForm(t *testing.T) {
+	type formData struct {
+		Name  string   `alias:"name"`
+		Page  int      `alias:"page"`
+		Tags  []string `alias:"tags"`
+		Order string   `form:"order_by"`
+	}
+	app := NewApp()
+	app.AnyFunc("/bind", func(ctx Context) {
+		var data formData
+		if err := ctx.BindForm(&data); err != nil {
+			ctx.Fatal(err)
+			return
+		}
+		fmt.Fprintf(ctx, "%s %d %v %s", data.Name, data.Page, data.Tags, data.Order)
+	})
+
+	err := app.PostRequest("/bind?name=query&page=2&tags=q1&order_by=id",
+		NewClientBodyForm(url.Values{
+			"name": {"body"},
+			"tags": {"b1", "b2"},
+		}),
+		NewClientCheckStatus(200),
+		NewClientCheckBody("body 2 [b1 b2] id"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/bind?name=query&tags=q1&tags=q2",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("query 0 [q1 q2] "),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.PostRequest("/bind?name=query",
+		NewClientBodyJSON(map[string]any{"name": "json"}),
+		NewClientCheckStatus(400),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.AnyFunc("/file", func(ctx Context) {
+		var data struct {
+			Name string                `alias:"name"`
+			File *multipart.FileHeader `alias:"file"`
+		}
+		if err := ctx.BindForm(&data); err != nil {
+			ctx.Fatal(err)
+			return
+		}
+		fmt.Fprintf(ctx, "%s %s %d", data.Name, data.File.Filename, data.File.Size)
+	})
+	app.AnyFunc("/file-mismatch", func(ctx Context) {
+		var data struct {
+			File int `alias:"file"`
+		}
+		if err := ctx.BindForm(&data); err != nil {
+			ctx.Fatal(err)
+			return
+		}
+	})
+	newFileBody := func() ClientBody {
+		body := NewClientBodyForm(url.Values{"name": {"upload"}})
+		body.AddFile("file", "app.txt", []byte("eudore"))
+		return body
+	}
+	err = app.PostRequest("/file", newFileBody(),
+		NewClientCheckStatus(200),
+		NewClientCheckBody("upload app.txt 6"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.PostRequest("/file-mismatch", newFileBody(),
+		NewClientCheckStatus(400),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextAsCurl(t *testing.T) {
+	app := NewApp()
+	app.AnyFunc("/curl", func(ctx Context) {
+		ctx.WriteString(ctx.AsCurl())
+	})
+
+	err := app.PostRequest("/curl?name=eudore",
+		http.Header{
+			HeaderAuthorization: {"Bearer secret"},
+			"X-Sample":          {"it's"},
+		},
+		strings.NewReader(`{"name":"eudore"}`),
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`curl -X 'POST' 'http://`),
+		NewClientCheckBody(`/curl?name=eudore'`),
+		NewClientCheckBody(`-H 'Authorization: ******'`),
+		NewClientCheckBody(`-H 'X-Sample: it'\''s'`),
+		NewClientCheckBody(`--data-raw '{"name":"eudore"}'`),
+		func(w *http.Response) error {
+			body, _ := io.ReadAll(w.Body)
+			if strings.Contains(string(body), "secret") {
+				return fmt.Errorf("authorization not masked: %s", body)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextBindMap(t *testing.T) {
+	app := NewApp()
+	app.PostFunc("/bind", func(ctx Context) error {
+		data, err := ctx.BindMap()
+		if err != nil {
+			return err
+		}
+		err = RequireKeys(data, "name", "user.id", "user.role")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(ctx, "%v %v", data["name"], GetAnyByPath(data, "user.id"))
+		return nil
+	})
+
+	err := app.PostRequest("/bind",
+		NewClientBodyJSON(map[string]any{
+			"name": "eudore",
+			"user": map[string]any{"id": 1, "role": "admin"},
+		}),
+		NewClientCheckStatus(200),
+		NewClientCheckBody("eudore 1"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.PostRequest("/bind",
+		NewClientBodyJSON(map[string]any{
+			"name": nil,
+			"user": map[string]any{"id": 1},
+		}),
+		NewClientCheckStatus(400),
+		NewClientCheckBody("missing required keys name, user.role"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.PostRequest("/bind",
+		NewClientHeader(HeaderContentType, MimeApplicationJSON),
+		strings.NewReader("{"),
+		NewClientCheckStatus(400),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	if RequireKeys(map[string]any{"name": ""}, "name") != nil {
+		t.Error("RequireKeys empty string must exist")
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextSetTrailer(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/trailer", func(ctx Context) {
+		ctx.SetTrailer("grpc-status", "1")
+		ctx.WriteString("trailer body")
+		ctx.Response().Flush()
+		ctx.SetTrailer("grpc-status", "0")
+		ctx.SetTrailer("grpc-message", "ok")
+	})
+	app.GetFunc("/length", func(ctx Context) {
+		ctx.SetHeader(HeaderContentLength, "6")
+		ctx.SetTrailer("grpc-status", "0")
+		ctx.WriteString("length")
+	})
+
+	checkTrailer := func(status, message string) func(*http.Response) error {
+		return func(w *http.Response) error {
+			_, _ = io.Copy(io.Discard, w.Body)
+			if w.Trailer.Get("Grpc-Status") != status ||
+				w.Trailer.Get("Grpc-Message") != message {
+				return fmt.Errorf("invalid trailer: %v", w.Trailer)
+			}
+			return nil
+		}
+	}
+	err := app.GetRequest("/trailer",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			// declared trailer keys are moved from Header to Trailer
+			_, ok := w.Trailer["Grpc-Status"]
+			if !ok {
+				return fmt.Errorf("invalid trailer declared: %v", w.Trailer)
+			}
+			return nil
+		},
+		checkTrailer("0", "ok"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/length",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("length"),
+		checkTrailer("", ""),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextFatalRegisterStatus(t *testing.T) {
+	errNotFound := errors.New("record not found")
+	RegisterErrorStatus(errNotFound, StatusNotFound)
+	RegisterErrorStatus(context.DeadlineExceeded, StatusGatewayTimeout)
+	defer RegisterErrorStatus(errNotFound, 0)
+	defer RegisterErrorStatus(context.DeadlineExceeded, 0)
+
+	app := NewApp()
+	app.GetFunc("/notfound", func(ctx Context) {
+		ctx.Fatal(fmt.Errorf("query user: %w", errNotFound))
+	})
+	app.GetFunc("/timeout", func(ctx Context) {
+		ctx.Fatal(context.DeadlineExceeded)
+	})
+	app.GetFunc("/status", func(ctx Context) {
+		ctx.Fatal(NewErrorWithStatus(errNotFound, StatusGone))
+	})
+	app.GetFunc("/unknown", func(ctx Context) {
+		ctx.Fatal(errors.New("unknown"))
+	})
+
+	for path, status := range map[string]int{
+		"/notfound": StatusNotFound,
+		"/timeout":  StatusGatewayTimeout,
+		"/status":   StatusGone,
+		"/unknown":  StatusInternalServerError,
+	} {
+		err := app.GetRequest(path, NewClientCheckStatus(status))
+		if err != nil {
+			t.Error(path, err)
+		}
+	}
+
+	RegisterErrorStatus(errNotFound, 0)
+	err := app.GetRequest("/notfound",
+		NewClientCheckStatus(StatusInternalServerError),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// uncomparable error does not panic when registered again.
+	RegisterErrorStatus(errorFields{"name"}, StatusBadRequest)
+	RegisterErrorStatus(errorFields{"name"}, StatusBadRequest)
+
+	app.CancelFunc()
+	app.Run()
+}
+
+type errorFields []string
+
+func (err errorFields) Error() string {
+	return "invalid fields: " + strings.Join(err, ",")
+}
+
+func TestContextAbort(t *testing.T) {
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.AddMiddleware(func(ctx Context) {
+		if ctx.GetQuery("token") == "" {
+			ctx.Abort(StatusForbidden, "forbidden")
+		}
+	})
+	app.GetFunc("/abort", func(ctx Context) {
+		ctx.WriteString("handler")
+	})
+	app.GetFunc("/written", func(ctx Context) {
+		ctx.WriteString("written")
+		ctx.Abort(StatusForbidden, "forbidden")
+		ctx.WriteString(" after")
+	}, func(ctx Context) {
+		ctx.WriteString(" next")
+	})
+
+	err := app.GetRequest("/abort",
+		NewClientCheckStatus(StatusForbidden),
+		func(w *http.Response) error {
+			var msg struct {
+				Status  int    `json:"status"`
+				Message string `json:"message"`
+			}
+			if w.Header.Get(HeaderContentType) != MimeApplicationJSONCharsetUtf8 {
+				return fmt.Errorf("invalid content type: %s",
+					w.Header.Get(HeaderContentType),
+				)
+			}
+			err := json.NewDecoder(w.Body).Decode(&msg)
+			if err != nil {
+				return err
+			}
+			if msg.Status != StatusForbidden || msg.Message != "forbidden" {
+				return fmt.Errorf("invalid abort message: %v", msg)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/written?token=1",
+		NewClientCheckStatus(StatusOK),
+		NewClientCheckBody("written"),
+		func(w *http.Response) error {
+			if w.Header.Get(HeaderContentLength) != "7" {
+				return fmt.Errorf("handler called after abort")
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextWriteAfterEnd(t *testing.T) {
+	ring := NewLoggerWriterRing(20)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.GetFunc("/end", func(ctx Context) {
+		ctx.WriteString("end")
+		ctx.End()
+		n, err := ctx.Write([]byte(" write"))
+		if n != 6 || err != nil {
+			t.Errorf("write after end: %d %v", n, err)
+		}
+		ctx.WriteString(" string")
+		ctx.Render("render")
+	})
+	app.GetFunc("/empty", func(ctx Context) {
+		ctx.End()
+		ctx.WriteString("empty")
+	})
+
+	err := app.GetRequest("/end",
+		NewClientCheckStatus(StatusOK),
+		func(w *http.Response) error {
+			body, _ := io.ReadAll(w.Body)
+			if string(body) != "end" {
+				return fmt.Errorf("extra bytes after end: %q", body)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/empty",
+		NewClientCheckStatus(StatusOK),
+		NewClientCheckBody("empty"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var discard int
+	for _, line := range ring.(interface{ Lines() []string }).Lines() {
+		if strings.Contains(line, "written after End") {
+			discard++
+		}
+	}
+	if discard < 2 {
+		t.Errorf("discard logs %d", discard)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextStatus(t *testing.T) {
+	var status int
+	app := NewApp()
+	app.AddMiddleware(func(ctx Context) {
+		if ctx.Status() != StatusOK {
+			t.Errorf("default status %d", ctx.Status())
+		}
+		ctx.Next()
+		status = ctx.Status()
+	})
+	app.GetFunc("/default", func(ctx Context) {
+		ctx.WriteString("default")
+	})
+	app.GetFunc("/created", func(ctx Context) {
+		ctx.WriteStatus(StatusCreated)
+		if ctx.Status() != StatusCreated {
+			t.Errorf("pending status %d", ctx.Status())
+		}
+		ctx.WriteString("created")
+	})
+	app.GetFunc("/nocontent", func(ctx Context) {
+		ctx.WriteStatus(StatusNoContent)
+	})
+	app.GetFunc("/notmodified", func(ctx Context) {
+		ctx.WriteHeader(StatusNotModified)
+		ctx.WriteStatus(StatusOK)
+	})
+
+	routes := []struct {
+		path   string
+		status int
+	}{
+		{"/default", StatusOK},
+		{"/created", StatusCreated},
+		{"/nocontent", StatusNoContent},
+		{"/notmodified", StatusNotModified},
+	}
+	for _, route := range routes {
+		status = 0
+		err := app.GetRequest(route.path, NewClientCheckStatus(route.status))
+		if err != nil {
+			t.Error(err)
+		}
+		if status != route.status {
+			t.Errorf("%s status %d, want %d", route.path, status, route.status)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextStreamJSON(t *testing.T) {
+	type Event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	app := NewApp()
+	app.GetFunc("/stream", func(ctx Context) {
+		w := ctx.StreamJSON()
+		for i := 1; i <= 3; i++ {
+			err := w.Encode(Event{i, fmt.Sprintf("event-%d", i)})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	})
+	app.GetFunc("/cancel", func(ctx Context) {
+		c, cancel := context.WithCancel(ctx.Context())
+		ctx.SetContext(c)
+		w := ctx.StreamJSON()
+		_ = w.Encode(Event{1, "event-1"})
+		cancel()
+		err := w.Encode(Event{2, "event-2"})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("encode after cancel: %v", err)
+		}
+	})
+
+	err := app.GetRequest("/stream",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			if w.Header.Get(HeaderContentType) != MimeApplicationNDJSON {
+				return fmt.Errorf("invalid content type: %s",
+					w.Header.Get(HeaderContentType),
+				)
+			}
+			decoder := json.NewDecoder(w.Body)
+			for i := 1; i <= 3; i++ {
+				var event Event
+				err := decoder.Decode(&event)
+				if err != nil {
+					return err
+				}
+				if event.ID != i || event.Name != fmt.Sprintf("event-%d", i) {
+					return fmt.Errorf("invalid event: %v", event)
+				}
+			}
+			if decoder.More() {
+				return fmt.Errorf("extra events")
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/cancel",
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`{"id":1,"name":"event-1"}`+"\n"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextEnd(t *testing.T) {
+	var calls []string
+	record := func(name string) HandlerFunc {
+		return func(Context) {
+			calls = append(calls, name)
+		}
+	}
+	deny := func(ctx Context) {
+		calls = append(calls, "deny")
+		ctx.WriteHeader(StatusForbidden)
+		ctx.WriteString("deny")
+		ctx.End()
+	}
+
+	app := NewApp()
+	api := app.Group("/api")
+	api.AddMiddleware(record("mw1"), deny)
+	api.AddMiddleware(record("mw2"))
+	api.GetFunc("/user", record("handler"), record("handler2"))
+
+	router := NewRouterCoreMux()
+	router.HandleFunc(MethodGet, "/nested", []HandlerFunc{record("inner"), deny, record("inner2")})
+	app.GetFunc("/nested", NewRouterFunc(router), record("outer"))
+
+	// handlers longer than DefaultContextMaxHandler
+	app.GetFunc("/long", func(ctx Context) {
+		hs := make([]HandlerFunc, DefaultContextMaxHandler+10)
+		for i := range hs {
+			hs[i] = record("long")
+		}
+		hs[0] = deny
+		ctx.SetHandlers(-1, hs)
+		ctx.Next()
+	})
+
+	for path, want := range map[string]string{
+		"/api/user": "mw1 deny",
+		"/nested":   "inner deny",
+		"/long":     "deny",
+	} {
+		calls = calls[:0]
+		err := app.GetRequest(path,
+			NewClientCheckStatus(403),
+			func(w *http.Response) error {
+				body, _ := io.ReadAll(w.Body)
+				if string(body) != "deny" {
+					return fmt.Errorf("invalid body %q", body)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(path, err)
+		}
+		if strings.Join(calls, " ") != want {
+			t.Errorf("%s invalid calls: %v", path, calls)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestContextLoggerScoped(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	helper := func(log Logger, msg string) {
+		log.WithField("helper", true).Info(msg)
+	}
+
+	app := NewApp()
+	app.AddMiddleware(
+		NewLoggerGroupFunc("", log),
+		NewRequestIDFunc(func(Context) string { return "request-1" }),
+	)
+	app.GetFunc("/logger", func(ctx Context) {
+		helper(ctx.Logger(), "helper1")
+		helper(ctx.Logger(), "helper2")
+		ctx.Info("context")
+	})
+	app.GetRequest("/logger", NewClientCheckStatus(200))
+
+	lines := ring.(interface{ Lines() []string }).Lines()
+	if len(lines) != 3 {
+		t.Fatalf("invalid logger lines: %q", lines)
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, `"x-request-id":"request-1"`) ||
+			strings.Contains(line, `"helper":true`) != (i < 2) ||
+			strings.Count(line, `"helper"`) > 1 {
+			t.Errorf("invalid logger fields: %s", line)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
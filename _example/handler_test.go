@@ -5,12 +5,15 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	. "github.com/eudore/eudore"
 )
@@ -89,6 +92,120 @@ func TestHandlerRoute(t *testing.T) {
 	app.Run()
 }
 
+func TestHandlerStaticWildcard(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/static/named/*path", NewHandlerFileSystems(".", "."))
+	app.GetFunc("/static/any/*", NewHandlerFileSystems(".", "."))
+	app.GetFunc("/static/index/*path autoindex=true", NewHandlerFileSystems(".", "."))
+	app.GetFunc("/param/*path", func(ctx Context) {
+		ctx.WriteString(ctx.GetParam(ParamWildcard))
+	})
+
+	for _, path := range []string{"/static/named/", "/static/any/"} {
+		err := app.GetRequest(path+"handler_test.go",
+			NewClientCheckStatus(200),
+			NewClientCheckBody("func TestHandlerStaticWildcard"),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+		app.GetRequest(path+"notfound.go", NewClientCheckStatus(404))
+	}
+	err := app.GetRequest("/static/index/", NewClientHeader(HeaderAccept, MimeApplicationJSON),
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`"handler_test.go"`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/param/a/b.js", NewClientCheckBody("a/b.js"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerMount(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "mux users %s", r.URL.Path)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "mux root %s", r.URL.Path)
+	})
+
+	app := NewApp()
+	app.AnyFunc("/api/*", NewHandlerMount("/api/", mux))
+	app.AnyFunc("/mux/*path", NewHandlerMount("", mux))
+	app.AnyFunc("/other/*", NewHandlerMount("/api", mux))
+
+	for path, body := range map[string]string{
+		"/api/users/1":   "mux users /users/1",
+		"/api/":          "mux root /",
+		"/mux/users/2":   "mux users /users/2",
+		"/mux/index.txt": "mux root /index.txt",
+	} {
+		err := app.GetRequest(path,
+			NewClientCheckStatus(200),
+			NewClientCheckBody(body),
+		)
+		if err != nil {
+			t.Error(path, err)
+		}
+	}
+	err := app.GetRequest("/other/users/1", NewClientCheckStatus(404))
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerRouterRender(t *testing.T) {
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.AddHandler("404", "", NewHandlerRouter404(nil))
+	app.AddHandler("405", "", NewHandlerRouter405(nil))
+	app.GetFunc("/index", HandlerEmpty)
+	app.GetFunc("/text/*", NewHandlerRouter404(HandlerDataRenderText))
+
+	jsonHeader := NewClientHeader(HeaderAccept, MimeApplicationJSON)
+	err := app.GetRequest("/404", jsonHeader,
+		NewClientCheckStatus(404),
+		NewClientCheckBody(`{"status":404,"message":"Not Found","path":"/404"}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.PostRequest("/index", jsonHeader,
+		NewClientCheckStatus(405),
+		NewClientCheckBody(`{"status":405,"message":"Method Not Allowed","path":"/index"}`),
+		func(w *http.Response) error {
+			if w.Header.Get(HeaderAllow) != "GET" {
+				return fmt.Errorf("invalid allow header: %v", w.Header)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/text/404", jsonHeader,
+		NewClientCheckStatus(404),
+		NewClientCheckBody("404 Not Found"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func BindTestErr(ctx Context, i any) error {
 	if ctx.GetHeader("Debug") == "binderr" {
 		return errors.New("test bind error")
@@ -218,6 +335,142 @@ func TestHandlerRegister(t *testing.T) {
 	app.Run()
 }
 
+func TestHandlerExtenderEmpty(t *testing.T) {
+	handler := http.NotFoundHandler()
+	he := NewHandlerExtenderBase()
+	if hs := he.CreateHandlers("/", handler); len(hs) != 0 {
+		t.Errorf("empty extender converts http.Handler: %v", hs)
+	}
+	if len(he.List()) != 0 {
+		t.Errorf("empty extender list: %v", he.List())
+	}
+	err := he.RegisterExtender("", NewHandlerHTTPHandler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hs := he.CreateHandlers("/", handler); len(hs) != 1 {
+		t.Errorf("extender not converts http.Handler: %v", hs)
+	}
+
+	size := len(DefaultHandlerExtender.List())
+	he = NewHandlerExtender()
+	if hs := he.CreateHandlers("/", handler); len(hs) != 1 {
+		t.Errorf("default extender not converts http.Handler: %v", hs)
+	}
+	_ = he.RegisterExtender("", func(*testing.T) HandlerFunc { return HandlerEmpty })
+	if len(he.List()) != size+1 || len(DefaultHandlerExtender.List()) != size {
+		t.Errorf("extender list %d default %d",
+			len(he.List()), len(DefaultHandlerExtender.List()),
+		)
+	}
+}
+
+func TestHandlerExtenderTreeScope(t *testing.T) {
+	type rpcHandler func(Context) any
+	extender := func(fn rpcHandler) HandlerFunc {
+		return func(ctx Context) {
+			_ = ctx.Render(fn(ctx))
+		}
+	}
+	var rpc rpcHandler = func(Context) any { return "v2" }
+
+	he := NewHandlerExtenderTree()
+	_ = he.RegisterExtender("", NewHandlerHTTPHandler)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = he.RegisterExtender("/api/v"+strconv.Itoa(i+3), extender)
+		}(i)
+		go func() {
+			defer wg.Done()
+			he.CreateHandlers("/api/v1/user", rpc)
+			he.List()
+		}()
+	}
+	wg.Wait()
+	_ = he.RegisterExtender("/api/v2", extender)
+
+	if hs := he.CreateHandlers("/api/v2/user", rpc); len(hs) != 1 {
+		t.Errorf("path-scoped extender not applied: %v", hs)
+	}
+	if hs := he.CreateHandlers("/api/v1/user", rpc); len(hs) != 0 {
+		t.Errorf("path-scoped extender applied outside prefix: %v", hs)
+	}
+	if hs := he.CreateHandlers("/api/v1/user", http.NotFoundHandler()); len(hs) != 1 {
+		t.Errorf("root extender not applied: %v", hs)
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyHandlerExtender, he)
+	app.AnyFunc("/api/v2/user", rpc)
+	app.GetRequest("/api/v2/user",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("v2"),
+	)
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerRenderNoContent(t *testing.T) {
+	DefaultHandlerRenderNoContent = true
+	defer func() {
+		DefaultHandlerRenderNoContent = false
+	}()
+
+	app := NewApp()
+	app.DeleteFunc("/nil", func(Context) (any, error) {
+		return nil, nil
+	})
+	app.PutFunc("/ptr", func(Context) (any, error) {
+		return (*request017)(nil), nil
+	})
+	app.PutFunc("/accepted", func(ctx Context) (any, error) {
+		ctx.WriteStatus(StatusAccepted)
+		return nil, nil
+	})
+	app.GetFunc("/data", func(Context) any {
+		return "data"
+	})
+
+	checkEmpty := func(w *http.Response) error {
+		body, _ := io.ReadAll(w.Body)
+		if len(body) != 0 {
+			return fmt.Errorf("no content body: %q", body)
+		}
+		return nil
+	}
+	errs := []error{
+		app.NewRequest("DELETE", "/nil",
+			NewClientCheckStatus(StatusNoContent), checkEmpty,
+		),
+		app.PutRequest("/ptr", NewClientCheckStatus(StatusNoContent)),
+		app.PutRequest("/accepted",
+			NewClientCheckStatus(StatusAccepted),
+			NewClientCheckBody(`"status": 202`),
+		),
+		app.GetRequest("/data",
+			NewClientCheckStatus(StatusOK),
+			NewClientCheckBody("data"),
+		),
+	}
+	DefaultHandlerRenderNoContent = false
+	errs = append(errs, app.NewRequest("DELETE", "/nil",
+		NewClientCheckStatus(StatusOK),
+		NewClientCheckBody(`"status": 200`),
+	))
+	for _, err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestHandlerList(t *testing.T) {
 	app := NewApp()
 	app.AddHandlerExtend("/", func(any) HandlerFunc {
@@ -274,6 +527,59 @@ func TestHandlerRPC(t *testing.T) {
 	app.Run()
 }
 
+func TestHandlerRenderCanceled(t *testing.T) {
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.AddMiddleware(func(ctx Context) {
+		if ctx.GetQuery("cancel") != "" {
+			c, cancel := context.WithCancel(ctx.Request().Context())
+			cancel()
+			ctx.SetRequest(ctx.Request().WithContext(c))
+		}
+	})
+
+	var calls int
+	app.GetFunc("/any", func(Context) any {
+		calls++
+		return "eudore"
+	})
+	app.GetFunc("/anyerr", func(Context) (any, error) {
+		calls++
+		return "eudore", nil
+	})
+	app.GetFunc("/rpc", func(Context, map[string]any) (any, error) {
+		calls++
+		return "eudore", nil
+	})
+
+	for _, path := range []string{"/any", "/anyerr", "/rpc"} {
+		err := app.GetRequest(path+"?cancel=1", NewClientCheckStatus(200),
+			func(w *http.Response) error {
+				if w.ContentLength > 0 || w.Header.Get(HeaderContentType) != "" {
+					return fmt.Errorf("canceled request render data")
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(path, err)
+		}
+		err = app.GetRequest(path, NewClientCheckStatus(200),
+			NewClientCheckBody("eudore"),
+		)
+		if err != nil {
+			t.Error(path, err)
+		}
+	}
+	if calls != 6 {
+		t.Error("handler calls", calls)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestHandlerFunc(t *testing.T) {
 	SetHandlerAliasName(new(request017), "")
 	SetHandlerAliasName(new(request017), "handlerHttp1-test")
@@ -288,3 +594,106 @@ func TestHandlerFunc(t *testing.T) {
 	}
 	t.Log(len(hs))
 }
+
+func TestHandlerNilChain(t *testing.T) {
+	app := NewApp()
+	err := app.AddHandlerExtend(nil)
+	if !errors.Is(err, ErrHandlerExtenderParamNotFunc) {
+		t.Error("AddHandlerExtend nil error:", err)
+	}
+	err = app.AddHandlerExtend(999)
+	if !errors.Is(err, ErrHandlerExtenderParamNotFunc) {
+		t.Error("AddHandlerExtend int error:", err)
+	}
+
+	app.AddMiddleware(func(ctx Context) {
+		index, handlers := ctx.GetHandlers()
+		hs := make([]HandlerFunc, 0, len(handlers)+2)
+		hs = append(hs, handlers[:index+1]...)
+		hs = append(hs, nil)
+		hs = append(hs, handlers[index+1:]...)
+		hs = append(hs, nil)
+		ctx.SetHandlers(index, hs)
+	})
+	app.GetFunc("/nil", func(ctx Context) {
+		ctx.WriteString("nil chain")
+	})
+
+	err = app.GetRequest("/nil", NewClientCheckStatus(200), NewClientCheckBody("nil chain"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerWriteBytesString(t *testing.T) {
+	app := NewApp()
+	app.GetFunc("/bytes", func(Context) []byte {
+		return []byte("bytes body")
+	})
+	app.GetFunc("/string", func(ctx Context) (string, error) {
+		if ctx.GetQuery("err") != "" {
+			return "", errors.New("string error")
+		}
+		return "string body", nil
+	})
+
+	reqs := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/bytes", 200, "bytes body"},
+		{"/string", 200, "string body"},
+		{"/string?err=1", 500, "string error"},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			http.Header{HeaderAccept: {MimeApplicationJSON}},
+			NewClientCheckStatus(r.status), NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerFileMapFS(t *testing.T) {
+	mapfs := fstest.MapFS{
+		"index.html":    {Data: []byte("<h1>index</h1>")},
+		"css/style.css": {Data: []byte("body{}")},
+	}
+
+	app := NewApp()
+	app.GetFunc("/mapfs/*", mapfs)
+	app.GetFunc("/httpfs/* autoindex=true", http.FS(mapfs))
+
+	reqs := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/mapfs/index.html", 200, "<h1>index</h1>"},
+		{"/mapfs/css/style.css", 200, "body{}"},
+		{"/mapfs/none.css", 404, ""},
+		{"/httpfs/css/style.css", 200, "body{}"},
+		{"/httpfs/css/", 200, "style.css"},
+		{"/httpfs/css/none.css", 404, ""},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			NewClientCheckStatus(r.status), NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
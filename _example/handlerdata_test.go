@@ -1,14 +1,21 @@
 package eudore_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -95,6 +102,343 @@ func TestHandlerDataBind(*testing.T) {
 	app.Run()
 }
 
+func TestHandlerDataBindEmpty(t *testing.T) {
+	type bindData struct {
+		Name string `json:"name" alias:"name"`
+		Size int    `json:"size" alias:"size"`
+	}
+	app := NewApp()
+	app.AnyFunc("/bind", func(ctx Context) {
+		data := bindData{Name: "default", Size: 10}
+		err := ctx.Bind(&data)
+		if err != nil {
+			ctx.Fatal(err)
+			return
+		}
+		fmt.Fprintf(ctx, "%s %d", data.Name, data.Size)
+	})
+
+	jsonHeader := http.Header{HeaderContentType: {MimeApplicationJSON}}
+	for _, check := range []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{MethodGet, "/bind", "default 10"},
+		{MethodGet, "/bind?name=eudore", "eudore 10"},
+		{MethodPost, "/bind?size=20", "default 20"},
+	} {
+		for _, options := range [][]any{{}, {jsonHeader}} {
+			options = append(options,
+				NewClientCheckStatus(200),
+				NewClientCheckBody(check.body),
+			)
+			err := app.NewRequest(check.method, check.path, options...)
+			if err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	// chunked empty body
+	err := app.PostRequest("/bind", jsonHeader, io.MultiReader(),
+		NewClientCheckStatus(200),
+		NewClientCheckBody("default 10"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataBindDecompress(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+	}
+	compress := func(encoding, data string) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		if encoding == "gzip" {
+			w = gzip.NewWriter(&buf)
+		} else {
+			w = zlib.NewWriter(&buf)
+		}
+		w.Write([]byte(data))
+		w.Close()
+		return buf.Bytes()
+	}
+	defer func(size int64) {
+		DefaultHandlerDataBindDecompressSize = size
+	}(DefaultHandlerDataBindDecompressSize)
+	DefaultHandlerDataBindDecompressSize = 64
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/data", func(ctx Context) (any, error) {
+		var data Data
+		err := ctx.Bind(&data)
+		return &data, err
+	})
+
+	large := `{"name":"` + strings.Repeat("eudore", 32) + `"}`
+	reqs := []struct {
+		encoding string
+		body     []byte
+		status   int
+		check    string
+	}{
+		{"gzip", compress("gzip", `{"name":"eudore"}`), 200, `"name":"eudore"`},
+		{"deflate", compress("deflate", `{"name":"eudore"}`), 200, `"name":"eudore"`},
+		{"", []byte(`{"name":"eudore"}`), 200, `"name":"eudore"`},
+		{"gzip", []byte(`{"name":"eudore"}`), 400, ""},
+		{"br", []byte(`{"name":"eudore"}`), 415, ""},
+		{"gzip", compress("gzip", large), 413, ""},
+	}
+	for _, r := range reqs {
+		h := http.Header{
+			HeaderAccept:      {MimeApplicationJSON},
+			HeaderContentType: {MimeApplicationJSON},
+		}
+		if r.encoding != "" {
+			h.Set(HeaderContentEncoding, r.encoding)
+		}
+		err := app.NewRequest("POST", "/data", h, bytes.NewReader(r.body),
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.check),
+		)
+		if err != nil {
+			t.Error(r.encoding, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataJSONFunc(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+	}
+	var marshals, unmarshals int
+	defer func(marshal func(any) ([]byte, error), unmarshal func([]byte, any) error) {
+		DefaultJSONMarshal = marshal
+		DefaultJSONUnmarshal = unmarshal
+	}(DefaultJSONMarshal, DefaultJSONUnmarshal)
+	DefaultJSONMarshal = func(v any) ([]byte, error) {
+		marshals++
+		return json.Marshal(v)
+	}
+	DefaultJSONUnmarshal = func(data []byte, v any) error {
+		unmarshals++
+		return json.Unmarshal(data, v)
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/data", func(ctx Context) (any, error) {
+		var data Data
+		err := ctx.Bind(&data)
+		return &data, err
+	})
+
+	err := app.NewRequest("POST", "/data", NewClientBodyJSON(&Data{"eudore"}),
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`"name": "eudore"`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	if marshals != 1 || unmarshals != 1 {
+		t.Errorf("json func calls marshal %d unmarshal %d", marshals, unmarshals)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataBindError(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/data", func(ctx Context) (any, error) {
+		var data Data
+		err := ctx.Bind(&data)
+		if err != nil {
+			return nil, err
+		}
+		if data.Name == "server" {
+			return nil, errors.New("server error")
+		}
+		return &data, nil
+	})
+
+	reqs := []struct {
+		body   string
+		status int
+		check  string
+	}{
+		{`{"name":"eudore"}`, 200, `"name":"eudore"`},
+		{`{"name":"eudore"`, 400, `"message":{"offset":16}`},
+		{`{"name":"eudore","age":"18"}`, 400, `"message":{"field":"age","offset":27,"type":"int","value":"string"}`},
+		{`{"name":"server"}`, 500, `"error":"server error"`},
+	}
+	for _, r := range reqs {
+		err := app.NewRequest("POST", "/data", strings.NewReader(r.body),
+			http.Header{
+				HeaderAccept:      {MimeApplicationJSON},
+				HeaderContentType: {MimeApplicationJSON},
+			},
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.check),
+		)
+		if err != nil {
+			t.Error(r.body, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataBindJSONPool(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/data", func(ctx Context) {
+		var data Data
+		err := ctx.Bind(&data)
+		if err != nil {
+			ctx.Fatal(err)
+			return
+		}
+		ctx.WriteString(data.Name)
+	})
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for i := 0; i < 16; i++ {
+				// body size changes to check the reset of the pooled buffer.
+				name := fmt.Sprintf("%d-%d-%s", n, i, strings.Repeat("x", (n*i)%7*100))
+				var body string
+				err := app.NewRequest("POST", "/data",
+					NewClientBodyJSON(&Data{name}),
+					NewClientCheckStatus(200),
+					NewClientParse(&body),
+				)
+				if err != nil || body != name {
+					t.Error(name, body, err)
+				}
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataGobMsgpack(t *testing.T) {
+	type Data struct {
+		Name  string   `json:"name"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+	}
+
+	app := NewApp()
+	app.PostFunc("/data", func(ctx Context) {
+		var data Data
+		err := ctx.Bind(&data)
+		if err != nil {
+			ctx.Fatal(err)
+			return
+		}
+		data.Count++
+		ctx.Render(&data)
+	})
+
+	in := &Data{Name: "eudore", Count: 1, Tags: []string{"a", "b"}}
+	check := func(out *Data) func(*http.Response) error {
+		return func(*http.Response) error {
+			if out.Name != in.Name || out.Count != 2 ||
+				strings.Join(out.Tags, ",") != "a,b" {
+				return fmt.Errorf("invalid data: %#v", out)
+			}
+			return nil
+		}
+	}
+
+	checkContentType := func(mime string) func(*http.Response) error {
+		return func(w *http.Response) error {
+			if w.Header.Get(HeaderContentType) != mime {
+				return fmt.Errorf("invalid content type: %s",
+					w.Header.Get(HeaderContentType),
+				)
+			}
+			return nil
+		}
+	}
+
+	var out Data
+	err := app.NewRequest("POST", "/data",
+		NewClientBodyGob(in),
+		NewClientHeader(HeaderAccept, MimeApplicationGob),
+		NewClientCheckStatus(200),
+		checkContentType(MimeApplicationGob),
+		NewClientParse(&out),
+		check(&out),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// the msgpack functions are not set, bind is not supported.
+	err = app.NewRequest("POST", "/data",
+		NewClientBodyMsgpack(in),
+		NewClientHeader(HeaderAccept, MimeApplicationMsgpack+", "+MimeApplicationJSON),
+		NewClientCheckStatus(StatusUnsupportedMediaType),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// use json as a stand-in for the msgpack library.
+	DefaultMsgpackMarshal = json.Marshal
+	DefaultMsgpackUnmarshal = json.Unmarshal
+	defer func() {
+		DefaultMsgpackMarshal = nil
+		DefaultMsgpackUnmarshal = nil
+	}()
+	out = Data{}
+	err = app.NewRequest("POST", "/data",
+		NewClientBodyMsgpack(in),
+		NewClientHeader(HeaderAccept, MimeApplicationMsgpack),
+		NewClientCheckStatus(200),
+		checkContentType(MimeApplicationMsgpack),
+		NewClientParse(&out),
+		check(&out),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestHandlerDataRender(*testing.T) {
 	type Data struct {
 		Name string `json:"name" xml:"name"`
@@ -155,6 +499,325 @@ func TestHandlerDataRender(*testing.T) {
 	app.Run()
 }
 
+func TestHandlerDataRenderJSONIndent(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+	}
+
+	app := NewApp()
+	app.GetFunc("/compact", func(ctx Context) any {
+		return &Data{"eudore"}
+	})
+	app.GetFunc("/indent", func(ctx Context) error {
+		return HandlerDataRenderJSONIndent(ctx, &Data{"eudore"})
+	})
+	app.GetFunc("/context", func(ctx Context) any {
+		ctx.SetValue(ContextKeyRenderIndent, true)
+		return &Data{"eudore"}
+	})
+
+	compact := "{\"name\":\"eudore\"}\n"
+	indent := "{\n\t\"name\": \"eudore\"\n}\n"
+	for _, req := range [][2]string{
+		{"/compact", compact},
+		{"/compact?pretty=1", indent},
+		{"/compact?pretty=true", indent},
+		{"/compact?pretty=0", compact},
+		{"/compact?pretty=false", compact},
+		{"/indent", indent},
+		{"/context", indent},
+	} {
+		var body string
+		err := app.GetRequest(req[0],
+			http.Header{HeaderAccept: {MimeApplicationJSON}},
+			NewClientCheckStatus(200),
+			NewClientParse(&body),
+			func(w *http.Response) error {
+				if w.Header.Get(HeaderContentType) != MimeApplicationJSONCharsetUtf8 {
+					return fmt.Errorf("invalid content type: %s", w.Header.Get(HeaderContentType))
+				}
+				return nil
+			},
+		)
+		if err != nil || body != req[1] {
+			t.Errorf("render %s error: %v %q", req[0], err, body)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataRenderJSONStream(t *testing.T) {
+	type Data struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	datas := make([]Data, DefaultHandlerDataRenderJSONStreamSize*3+1)
+	for i := range datas {
+		datas[i] = Data{i, "eudore"}
+	}
+
+	app := NewApp()
+	// record the largest write to bound the memory used by the stream.
+	var maxWrite int64
+	app.AddMiddleware(func(ctx Context) {
+		ctx.SetResponse(&responseWriterMax{ctx.Response(), &maxWrite})
+	})
+	app.GetFunc("/slice", func(ctx Context) any {
+		return datas
+	})
+	app.GetFunc("/items", func(ctx Context) any {
+		return make(renderItems, DefaultHandlerDataRenderJSONStreamSize+1)
+	})
+	app.GetFunc("/error", func(ctx Context) any {
+		items := make([]any, DefaultHandlerDataRenderJSONStreamSize+1)
+		items[1] = make(chan int)
+		return items
+	})
+	app.GetFunc("/chan", func(ctx Context) any {
+		ch := make(chan Data)
+		go func() {
+			for i := 0; i < 3; i++ {
+				ch <- datas[i]
+			}
+			close(ch)
+		}()
+		return ch
+	})
+	app.GetFunc("/empty", func(ctx Context) any {
+		ch := make(chan Data)
+		close(ch)
+		return ch
+	})
+
+	compact, _ := json.Marshal(datas)
+	indent, _ := json.MarshalIndent(datas, "", "\t")
+	chanCompact, _ := json.Marshal(datas[:3])
+	chanIndent, _ := json.MarshalIndent(datas[:3], "", "\t")
+	for _, req := range []struct {
+		path string
+		body []byte
+	}{
+		{"/slice", compact},
+		{"/slice?pretty=1", indent},
+		{"/chan", chanCompact},
+		{"/chan?pretty=1", chanIndent},
+		{"/empty", []byte("[]")},
+	} {
+		err := app.GetRequest(req.path,
+			http.Header{HeaderAccept: {MimeApplicationJSON}},
+			NewClientCheckStatus(200),
+			func(w *http.Response) error {
+				body, _ := io.ReadAll(w.Body)
+				if !json.Valid(body) || !bytes.Equal(body, append(req.body, '\n')) {
+					return fmt.Errorf("invalid stream body: %.64q", body)
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(req.path, err)
+		}
+	}
+	if maxWrite*2 > int64(len(compact)) {
+		t.Errorf("stream write %d bytes, body %d bytes", maxWrite, len(compact))
+	}
+
+	err := app.GetRequest("/items",
+		http.Header{HeaderAccept: {MimeApplicationJSON}},
+		NewClientCheckStatus(200),
+		NewClientCheckBody(`{"items":1025}`),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/error", NewClientCheckStatus(500),
+		func(w *http.Response) error {
+			body, _ := io.ReadAll(w.Body)
+			if bytes.HasPrefix(body, []byte("[")) {
+				return fmt.Errorf("stream partial body: %.64q", body)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+type renderItems []int
+
+func (items renderItems) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"items":%d}`, len(items))), nil
+}
+
+type responseWriterMax struct {
+	ResponseWriter
+	max *int64
+}
+
+func (w *responseWriterMax) Write(b []byte) (int, error) {
+	if int64(len(b)) > *w.max {
+		*w.max = int64(len(b))
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func TestHandlerDataRenderJSONP(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSONP)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.GetFunc("/data", func(ctx Context) any {
+		return &Data{"eudore"}
+	})
+
+	reqs := []struct {
+		path   string
+		status int
+		body   string
+		mime   string
+	}{
+		{"/data?callback=jQuery.cb_1", 200, `/**/jQuery.cb_1({"name":"eudore"});`, MimeApplicationJavascript},
+		{"/data?callback=alert(1)//", 400, "invalid jsonp callback", MimeApplicationJSON},
+		{"/data?callback=" + strings.Repeat("a", 129), 400, "invalid jsonp callback", MimeApplicationJSON},
+		{"/data", 200, `"name": "eudore"`, MimeApplicationJSON},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.body),
+			func(w *http.Response) error {
+				if !strings.HasPrefix(w.Header.Get(HeaderContentType), r.mime) {
+					return fmt.Errorf("invalid content type: %s", w.Header.Get(HeaderContentType))
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataRenderEnvelope(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, NewHandlerDataRenderEnvelope(nil))
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.GetFunc("/data", func(ctx Context) any {
+		return &Data{"eudore"}
+	})
+	app.GetFunc("/err", func(ctx Context) error {
+		return NewErrorWithStatusCode(errors.New("not found user"), 404, 10001)
+	})
+	app.GetFunc("/status", func(ctx Context) error {
+		return NewErrorWithStatus(errors.New("forbidden"), 403)
+	})
+
+	reqs := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/data", 200, `{"code":0,"data":{"name":"eudore"},"msg":"ok"}`},
+		{"/err", 404, `{"code":10001,"msg":"not found user"}`},
+		{"/status", 403, `{"code":403,"msg":"forbidden"}`},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			http.Header{HeaderAccept: {MimeApplicationJSON}},
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataRenderCSV(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		ID       int      `alias:"id"`
+		Name     string   `json:"name"`
+		Password string   `json:"-"`
+		Email    string   `json:"email,omitempty"`
+		Address  Address  `json:"address"`
+		Tags     []string `json:"tags"`
+		Score    float64
+		private  string
+	}
+
+	app := NewApp()
+	app.GetFunc("/users", func(ctx Context) any {
+		return []*User{
+			{1, "eudore", "pass", "a@b.c", Address{"sz"}, []string{"a", "b"}, 1.5, ""},
+			{2, "o'neil, \"jr\"", "", "", Address{}, nil, 0, ""},
+		}
+	})
+	app.GetFunc("/maps", func(ctx Context) any {
+		return []map[string]any{
+			{"name": "eudore", "id": 1},
+			{"name": "godoc", "id": 2},
+		}
+	})
+	app.GetFunc("/string", func(ctx Context) any {
+		return "eudore"
+	})
+	app.GetFunc("/nil", func(ctx Context) any {
+		return nil
+	})
+	app.GetFunc("/nilptr", func(ctx Context) any {
+		return (*[]User)(nil)
+	})
+
+	reqs := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/users", 200, "id,name,email,address,tags,Score\n" +
+			"1,eudore,a@b.c,\"{\"\"city\"\":\"\"sz\"\"}\",\"[\"\"a\"\",\"\"b\"\"]\",1.5\n" +
+			"2,\"o'neil, \"\"jr\"\"\",,\"{\"\"city\"\":\"\"\"\"}\",null,0\n"},
+		{"/maps", 200, "id,name\n1,eudore\n2,godoc\n"},
+		{"/string", 200, `"message": "eudore"`},
+		{"/nil", 200, `"status": 200`},
+		{"/nilptr", 200, `"status": 200`},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path,
+			http.Header{HeaderAccept: {MimeTextCSV}},
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 //go:embed handlerdata_test.go
 var handlerdatafile embed.FS
 
@@ -206,6 +869,44 @@ func TestHandlerDataRenderTemplates(*testing.T) {
 	app.Run()
 }
 
+func TestHandlerDataRenderTemplatesDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(dir+"/index.html", []byte(`<h1>{{.Name}}</h1>`), 0o600)
+	os.WriteFile(dir+"/error.html", []byte(`<h1>{{.Name.Value}}</h1>`), 0o600)
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, NewHandlerDataRenderTemplates(nil, nil, dir+"/*.html"))
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.GetFunc("/index template=index.html", func(ctx Context) any {
+		return map[string]string{"Name": "eudore"}
+	})
+	app.GetFunc("/error template=error.html", func(ctx Context) any {
+		return map[string]string{"Name": "eudore"}
+	})
+	app.GetFunc("/param", func(ctx Context) any {
+		ctx.SetParam(ParamTemplate, "index.html")
+		return map[string]string{"Name": "param"}
+	})
+
+	reqs := [][3]any{
+		{"/index", 200, "<h1>eudore</h1>"},
+		{"/param", 200, "<h1>param</h1>"},
+		{"/error", 500, "can't evaluate field Value"},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r[0].(string),
+			NewClientCheckStatus(r[1].(int)),
+			NewClientCheckBody(r[2].(string)),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 type dataValidate01 struct {
 	ID     *int   `json:"id" xml:"id" valid:"nozero,omitempty"`
 	Child  []int  `json:"child" xml:"child" valid:"nozero,omitempty"`
@@ -320,6 +1021,170 @@ func TestHandlerDataValidateStruct(*testing.T) {
 	app.Run()
 }
 
+func TestHandlerDataValidateErrors(t *testing.T) {
+	type Data struct {
+		Name  string `json:"name" valid:"nozero,len>4"`
+		Age   int    `json:"age" valid:"min=18"`
+		Email string `json:"email" valid:"nozero"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyBind, NewHandlerDataFuncs(
+		NewHandlerDataBinds(nil),
+		NewHandlerDataValidateStruct(app),
+	))
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/struct", func(ctx Context) error {
+		var data Data
+		return ctx.Bind(&data)
+	})
+	app.PostFunc("/slice", func(ctx Context) error {
+		var data []Data
+		return ctx.Bind(&data)
+	})
+
+	check := func(fields ...string) func(*http.Response) error {
+		return func(w *http.Response) error {
+			var msg struct {
+				Message map[string]string `json:"message"`
+			}
+			err := json.NewDecoder(w.Body).Decode(&msg)
+			if err != nil {
+				return err
+			}
+			if len(msg.Message) != len(fields) {
+				return fmt.Errorf("invalid fields %v", msg.Message)
+			}
+			for _, field := range fields {
+				if msg.Message[field] == "" {
+					return fmt.Errorf("not found field %s in %v", field, msg.Message)
+				}
+			}
+			return nil
+		}
+	}
+
+	err := app.NewRequest("POST", "/struct",
+		NewClientBodyJSON(map[string]any{"name": "ab", "age": 10}),
+		NewClientCheckStatus(400),
+		check("Name", "Age", "Email"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.NewRequest("POST", "/slice",
+		NewClientBodyJSON([]map[string]any{
+			{"name": "eudore", "age": 20, "email": "eudore@example.com"},
+			{"name": "eudore", "age": 10},
+		}),
+		NewClientCheckStatus(400),
+		check("1.Age", "1.Email"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.NewRequest("POST", "/struct",
+		NewClientBodyJSON(map[string]any{"name": "eudore", "age": 20, "email": "eudore@example.com"}),
+		NewClientCheckStatus(200),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataValidateOmitempty(t *testing.T) {
+	type Data struct {
+		Name  string `json:"name" valid:"nozero,omitempty,len>4"`
+		Email string `json:"email" valid:"omitempty,mail"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyBind, NewHandlerDataFuncs(
+		NewHandlerDataBinds(nil),
+		NewHandlerDataValidateStruct(app),
+	))
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/data", func(ctx Context) error {
+		var data Data
+		return ctx.Bind(&data)
+	})
+
+	reqs := []struct {
+		data   map[string]any
+		status int
+		check  string
+	}{
+		{map[string]any{"name": "eudore"}, 200, ""},
+		{map[string]any{"name": "eudore", "email": "eudore@example.com"}, 200, ""},
+		{map[string]any{"name": "eudore", "email": "eudore"}, 400, `"Email"`},
+		{map[string]any{"email": "eudore@example.com"}, 400, `"Name"`},
+	}
+	for _, r := range reqs {
+		err := app.NewRequest("POST", "/data", NewClientBodyJSON(r.data),
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.check),
+		)
+		if err != nil {
+			t.Error(r.data, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestHandlerDataBindAndValidate(t *testing.T) {
+	type Data struct {
+		Name string `json:"name" valid:"len>4"`
+	}
+
+	app := NewApp()
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.PostFunc("/data", func(ctx Context) {
+		var data Data
+		err := ctx.BindAndValidate(&data)
+		var verrs ValidationErrors
+		switch {
+		case errors.As(err, &verrs):
+			ctx.WriteStatus(422)
+			ctx.WriteString("validate " + verrs["Name"])
+		case err != nil:
+			ctx.WriteStatus(400)
+			ctx.WriteString("bind " + err.Error())
+		default:
+			ctx.WriteString("hello " + data.Name)
+		}
+	})
+
+	reqs := []struct {
+		body   string
+		status int
+		check  string
+	}{
+		{`{"name":"eudore"}`, 200, "hello eudore"},
+		{`{"name":"eudore"`, 400, "bind "},
+		{`{"name":"ab"}`, 422, "validate "},
+	}
+	for _, r := range reqs {
+		err := app.NewRequest("POST", "/data", strings.NewReader(r.body),
+			http.Header{HeaderContentType: {MimeApplicationJSON}},
+			NewClientCheckStatus(r.status),
+			NewClientCheckBody(r.check),
+		)
+		if err != nil {
+			t.Error(r.body, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestHandlerDataFilterRule(*testing.T) {
 	type LoggerConfig struct {
 		Stdout   bool   `json:"stdout" xml:"stdout" alias:"stdout"`
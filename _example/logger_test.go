@@ -1,10 +1,14 @@
 package eudore_test
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"runtime"
 	"strings"
@@ -193,6 +197,181 @@ func loggerWriteData(log Logger) {
 	log.WithField("depth", "stack").Info("depth")
 }
 
+type loggerCapture struct {
+	entries []string
+}
+
+func (h *loggerCapture) HandlerPriority() int {
+	return DefaultLoggerPriorityWriterStdout
+}
+
+func (h *loggerCapture) HandlerEntry(entry *LoggerEntry) {
+	h.entries = append(h.entries, string(entry.Buffer))
+}
+
+type marshaInvalid struct{}
+
+func (marshaInvalid) MarshalJSON() ([]byte, error) {
+	return []byte(`{"name":"eudore",`), nil
+}
+
+type marshaIndent struct{}
+
+func (marshaIndent) MarshalJSON() ([]byte, error) {
+	return []byte("{\n\t\"name\": \"eudore\"\n}"), nil
+}
+
+func TestLoggerFormatterJSONMarshaler(t *testing.T) {
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+	log.WithField("field", marshaInvalid{}).Info("invalid")
+	log.WithField("field", marshaIndent{}).Info("indent")
+
+	for _, entry := range capture.entries {
+		if !json.Valid([]byte(entry)) || strings.Count(entry, "\n") != 1 {
+			t.Fatalf("invalid log line: %q", entry)
+		}
+	}
+	if !strings.Contains(capture.entries[0], `"field":"Logger: MarshalJSON for type eudore_test.marshaInvalid returned invalid json`) ||
+		!strings.Contains(capture.entries[1], `"field":{"name":"eudore"}`) {
+		t.Fatalf("invalid log data: %v", capture.entries)
+	}
+}
+
+func TestLoggerFormatterJSONMarshal(t *testing.T) {
+	var marshals int
+	defer func(marshal func(any) ([]byte, error)) {
+		DefaultJSONMarshal = marshal
+	}(DefaultJSONMarshal)
+	DefaultJSONMarshal = func(v any) ([]byte, error) {
+		marshals++
+		return json.Marshal(v)
+	}
+
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+	log.WithField("field", marshaIndent{}).Info("indent")
+	if marshals != 1 || !strings.Contains(capture.entries[0], `"field":{"name":"eudore"}`) {
+		t.Fatalf("invalid log marshal %d: %v", marshals, capture.entries)
+	}
+}
+
+type stringerPanic struct{}
+
+func (stringerPanic) String() string {
+	panic("stringer panic")
+}
+
+func TestLoggerFormatterPanic(t *testing.T) {
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+	log.WithField("field", stringerPanic{}).WithField("next", 1).Info("json")
+	log = NewLogger(&LoggerConfig{
+		Handlers:  []LoggerHandler{capture},
+		Formatter: "text",
+	})
+	log.WithField("field", stringerPanic{}).Info("text")
+
+	if !json.Valid([]byte(capture.entries[0])) ||
+		!strings.Contains(capture.entries[0], `"field":"<panic: stringer panic>","next":1`) ||
+		!strings.Contains(capture.entries[1], `<panic: stringer panic>`) {
+		t.Fatalf("invalid log data: %q", capture.entries)
+	}
+}
+
+func TestLoggerFormatterTags(t *testing.T) {
+	type aliasData struct {
+		Name  string `alias:"name" json:"json_name"`
+		Age   int    `alias:"age,omitempty"`
+		Skip  string `alias:"-"`
+		Email string `json:"email"`
+	}
+	defer func(tags []string) {
+		DefaultLoggerFormatterTags = tags
+	}(DefaultLoggerFormatterTags)
+	DefaultLoggerFormatterTags = []string{"alias", "json"}
+
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+	log.WithField("data", aliasData{"eudore", 0, "skip", "a@b.c"}).Info()
+	if !strings.Contains(capture.entries[0], `"data":{"name":"eudore","email":"a@b.c"}`) {
+		t.Fatalf("invalid log tags: %v", capture.entries)
+	}
+}
+
+func TestLoggerFormatterOmitEmpty(t *testing.T) {
+	type omitInner struct {
+		Tags []string `json:"tags,omitempty"`
+	}
+	type omitData struct {
+		Name  string            `json:"name"`
+		Tags  []string          `json:"tags,omitempty"`
+		Attrs map[string]string `json:"attrs,omitempty"`
+		Inner omitInner         `json:"inner,omitempty"`
+		List  []string          `json:"list"`
+	}
+	defer func() {
+		DefaultLoggerFormatterOmitEmptyStruct = false
+	}()
+
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+	data := omitData{
+		Name:  "eudore",
+		Tags:  []string{},
+		Attrs: map[string]string{},
+		Inner: omitInner{Tags: []string{}},
+		List:  []string{},
+	}
+	log.WithField("data", data).Info()
+	DefaultLoggerFormatterOmitEmptyStruct = true
+	log.WithField("data", data).Info()
+	data.Tags = []string{"a"}
+	log.WithField("data", data).Info()
+
+	for i, str := range []string{
+		`"data":{"name":"eudore","inner":{},"list":[]}`,
+		`"data":{"name":"eudore","list":[]}`,
+		`"data":{"name":"eudore","tags":["a"],"list":[]}`,
+	} {
+		if !strings.Contains(capture.entries[i], str) {
+			t.Errorf("omitempty error: %s not contains %s", capture.entries[i], str)
+		}
+	}
+}
+
+func TestLoggerFormatterFloat(t *testing.T) {
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+	log.WithField("float32", float32(0.1)).WithField("float64", 0.1).Info()
+	log.WithField("complex64", complex64(complex(0.1, 0.2))).Info()
+	DefaultLoggerFormatterFloatPrecision = 2
+	log.WithField("float32", float32(1.0/3)).WithField("float64", 2.0/3).Info()
+	DefaultLoggerFormatterFloatPrecision = -1
+
+	for i, str := range []string{
+		`"float32":0.1,"float64":0.1`,
+		`"complex64":"0.1+0.2i"`,
+		`"float32":0.33,"float64":0.67`,
+	} {
+		if !strings.Contains(capture.entries[i], str) {
+			t.Errorf("float format error: %s not contains %s", capture.entries[i], str)
+		}
+	}
+}
+
 type logConfig struct {
 	Level1 LoggerLevel `alias:"level" json:"level1"`
 	Level2 LoggerLevel `alias:"level2" json:"level2"`
@@ -344,6 +523,230 @@ func TestLoggerWriterFile(t *testing.T) {
 	}()
 }
 
+func TestLoggerReload(t *testing.T) {
+	dir := t.TempDir()
+	file1, file2 := dir+"/app1.log", dir+"/app2.log"
+	log := NewLogger(&LoggerConfig{
+		Path:   file1,
+		Reload: true,
+	})
+	log.(interface{ Mount(context.Context) }).Mount(context.Background())
+	log.Info("write file1")
+	log.Debug("write debug1")
+
+	reload := log.(interface{ Reload(*LoggerConfig) error })
+	err := reload.Reload(&LoggerConfig{
+		Level:     LoggerInfo,
+		Path:      file2,
+		AsyncSize: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("write file2")
+	log.Debug("write debug2")
+	log.WithField("key", "value").Info("write field2")
+	if log.GetLevel() != LoggerInfo {
+		t.Error("reload level:", log.GetLevel())
+	}
+	log.(interface{ Unmount(context.Context) }).Unmount(context.Background())
+
+	body1, _ := os.ReadFile(file1)
+	body2, _ := os.ReadFile(file2)
+	if !strings.Contains(string(body1), "write file1") ||
+		!strings.Contains(string(body1), "write debug1") ||
+		strings.Contains(string(body1), "file2") {
+		t.Errorf("file1 data: %s", body1)
+	}
+	if !strings.Contains(string(body2), "write file2") ||
+		!strings.Contains(string(body2), "write field2") ||
+		strings.Contains(string(body2), "debug2") {
+		t.Errorf("file2 data: %s", body2)
+	}
+
+	err = NewLogger(&LoggerConfig{}).(interface{ Reload(*LoggerConfig) error }).
+		Reload(&LoggerConfig{})
+	if !errors.Is(err, ErrLoggerReloadDisabled) {
+		t.Error("reload disabled:", err)
+	}
+	err = reload.Reload(&LoggerConfig{Path: dir + "/" + strings.Repeat("-", 256) + ".log"})
+	if err == nil {
+		t.Error("reload invalid path must error")
+	}
+}
+
+func TestLoggerWriterRing(t *testing.T) {
+	ring := NewLoggerWriterRing(3)
+	log := NewLogger(&LoggerConfig{
+		Handlers:  []LoggerHandler{ring},
+		Formatter: "text",
+	})
+	for i := 0; i < 5; i++ {
+		log.Info("ring", i)
+	}
+
+	app := NewApp()
+	app.GetFunc("/logs", ring)
+	var lines []string
+	err := app.GetRequest("/logs",
+		NewClientCheckStatus(200),
+		NewClientParse(&lines),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "ring 2") ||
+		!strings.HasSuffix(lines[2], "ring 4") {
+		t.Fatalf("invalid ring lines: %q", lines)
+	}
+
+	err = app.GetRequest("/logs?format=text",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			body, _ := io.ReadAll(w.Body)
+			if strings.Count(string(body), "\n") != 3 ||
+				!strings.HasSuffix(string(body), "ring 4\n") {
+				return fmt.Errorf("invalid ring text: %q", body)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestLoggerWriterNetwork(t *testing.T) {
+	defer func(d time.Duration) {
+		DefaultLoggerWriterNetworkBackoffMin = d
+	}(DefaultLoggerWriterNetworkBackoffMin)
+	DefaultLoggerWriterNetworkBackoffMin = 10 * time.Millisecond
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	lines := make(chan string, 100)
+	serve := func(ln net.Listener) {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			go func() {
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}
+	wait := func(msg string) []string {
+		var recv []string
+		for {
+			select {
+			case line := <-lines:
+				recv = append(recv, line)
+				if strings.Contains(line, msg) {
+					return recv
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("wait log %s timeout, received: %v", msg, recv)
+			}
+		}
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	go serve(ln)
+
+	ring := NewLoggerWriterRing(20)
+	writer := NewLoggerWriterNetwork("tcp", addr, 4)
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring, writer},
+	})
+	log.Info("connected")
+	wait("connected")
+
+	// disconnect, the first write may be lost before the reset is received.
+	ln.Close()
+	mu.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	log.Info("lost")
+	time.Sleep(50 * time.Millisecond)
+	for i := 1; i <= 6; i++ {
+		log.Infof("offline-%d", i)
+	}
+	// wait for the background goroutine to detect the disconnection.
+	time.Sleep(50 * time.Millisecond)
+
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go serve(ln)
+	err = writer.(interface{ Sync() error }).Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("reconnected")
+
+	recv := strings.Join(wait("reconnected"), "\n")
+	for i := 3; i <= 6; i++ {
+		if !strings.Contains(recv, fmt.Sprintf("offline-%d", i)) {
+			t.Errorf("buffered log offline-%d not received: %s", i, recv)
+		}
+	}
+	meta := writer.(interface{ Metadata() any }).Metadata().(MetadataLogger)
+	if !meta.Health || meta.Count[LoggerDiscard] == 0 {
+		t.Errorf("invalid metadata: %#v", meta)
+	}
+	if len(ring.(interface{ Lines() []string }).Lines()) != 9 {
+		t.Errorf("ring lines: %v", ring.(interface{ Lines() []string }).Lines())
+	}
+	writer.(interface{ Unmount(context.Context) }).Unmount(context.Background())
+}
+
+func TestLoggerHookRepeat(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{
+		Handlers:   []LoggerHandler{ring},
+		HookRepeat: time.Millisecond * 20,
+	})
+	ctx := context.WithValue(context.Background(), ContextKeyLogger, log)
+	log.(interface{ Mount(context.Context) }).Mount(ctx)
+
+	for i := 0; i < 4; i++ {
+		log.WithField("stack", []string{"main.go:10"}).Error("db error")
+	}
+	log.Info("info does not interrupt")
+	log.WithField("stack", []string{"main.go:10"}).Error("db error")
+	log.Error("other error")
+	log.Error("other error")
+	time.Sleep(time.Millisecond * 50)
+
+	lines := ring.(interface{ Lines() []string }).Lines()
+	if len(lines) != 5 ||
+		!strings.Contains(lines[2], `"repeated":4`) ||
+		!strings.Contains(lines[2], `"stack":["main.go:10"]`) ||
+		!strings.Contains(lines[4], `"repeated":1`) {
+		t.Fatalf("invalid repeat lines: %q", lines)
+	}
+	log.(interface{ Unmount(context.Context) }).Unmount(ctx)
+}
+
 func TestNewLoggerWriterRotate(t *testing.T) {
 	defer os.RemoveAll("logger")
 	{
@@ -394,6 +797,33 @@ func TestNewLoggerWriterRotate(t *testing.T) {
 	}
 }
 
+func TestLoggerCallerTrimPrefix(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	root := file[:strings.LastIndex(file, "/_example/")+1]
+	rel := file[len(root):] + ":"
+	defer func() {
+		DefaultLoggerCallerTrimPrefix = ""
+	}()
+
+	DefaultLoggerCallerTrimPrefix = "/nonexistent/module/"
+	_, name := GetCallerFuncFile(1)
+	if !strings.Contains(name, rel) {
+		t.Errorf("untrimmed file name: %s", name)
+	}
+
+	DefaultLoggerCallerTrimPrefix = root
+	for i := 0; i < 2; i++ {
+		_, name = GetCallerFuncFile(1)
+		if !strings.HasPrefix(name, rel) {
+			t.Errorf("trim module prefix %s: %s", root, name)
+		}
+	}
+	stack := GetCallerStacks(2)
+	if len(stack) == 0 || !strings.HasPrefix(stack[0], rel) {
+		t.Errorf("trim module prefix %s: %v", root, stack)
+	}
+}
+
 func TestLoggerMonkeyErr(t *testing.T) {
 	defer func() {
 		t.Logf("MonkeyErr recover %v", recover())
@@ -147,6 +147,102 @@ func TestMiddlewareCSRF(*testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareIdempotency(t *testing.T) {
+	var count int
+	app := NewApp()
+	app.AddMiddleware(NewIdempotencyFunc(nil, time.Minute))
+	app.PostFunc("/order", func(ctx Context) {
+		count++
+		ctx.SetHeader("X-Order", fmt.Sprint(count))
+		ctx.WriteHeader(StatusCreated)
+		fmt.Fprintf(ctx, "order %d", count)
+	})
+	app.PostFunc("/error", func(ctx Context) {
+		count++
+		ctx.WriteHeader(StatusInternalServerError)
+	})
+	app.PostFunc("/wait", func(ctx Context) {
+		err := app.NewRequest(MethodPost, "/wait",
+			http.Header{HeaderIdempotencyKey: {"wait"}},
+			NewClientCheckStatus(StatusConflict),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	check := func(key, body string) []any {
+		return []any{
+			http.Header{HeaderIdempotencyKey: {key}},
+			NewClientCheckStatus(StatusCreated),
+			NewClientCheckBody(body),
+			func(w *http.Response) error {
+				if w.Header.Get("X-Order") != body[6:] {
+					return fmt.Errorf("invalid header: %v", w.Header)
+				}
+				return nil
+			},
+		}
+	}
+	for _, err := range []error{
+		app.PostRequest("/order", check("key1", "order 1")...),
+		app.PostRequest("/order", check("key1", "order 1")...),
+		app.PostRequest("/order", check("key2", "order 2")...),
+		app.PostRequest("/order", NewClientCheckBody("order 3")),
+		app.PostRequest("/error", http.Header{HeaderIdempotencyKey: {"key1"}}),
+		app.PostRequest("/error", http.Header{HeaderIdempotencyKey: {"key1"}}),
+		app.PostRequest("/wait", http.Header{HeaderIdempotencyKey: {"wait"}}),
+	} {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if count != 5 {
+		t.Errorf("invalid handler count %d", count)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareIdempotencyScope(t *testing.T) {
+	var count int
+	app := NewApp()
+	app.AddMiddleware(
+		func(ctx Context) {
+			ctx.SetParam(ParamUserid, ctx.GetHeader("X-User"))
+		},
+		NewIdempotencyFunc(nil, time.Minute),
+	)
+	app.PostFunc("/order", func(ctx Context) {
+		count++
+		fmt.Fprintf(ctx, "order %d", count)
+	})
+
+	order := func(user, body, resp string) error {
+		return app.PostRequest("/order",
+			http.Header{HeaderIdempotencyKey: {"key"}, "X-User": {user}},
+			strings.NewReader(body),
+			NewClientCheckStatus(StatusOK),
+			NewClientCheckBody(resp),
+		)
+	}
+	for _, err := range []error{
+		order("alice", "item=1", "order 1"),
+		order("alice", "item=1", "order 1"),
+		order("bob", "item=1", "order 2"),
+		order("alice", "item=2", "order 3"),
+		order("bob", "item=1", "order 2"),
+	} {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareDump(*testing.T) {
 	type dumpMessage struct {
 		Time          string      `json:"time"`
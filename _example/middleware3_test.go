@@ -304,6 +304,49 @@ func TestMiddlewareRateRequest(*testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareRouteTag(t *testing.T) {
+	app := NewApp()
+	app.AddMiddleware(NewCacheFunc(time.Hour), NewRateRequestFunc(100, 100))
+	var count int
+	handler := func(ctx Context) {
+		count++
+		ctx.WriteString(strconv.Itoa(count))
+	}
+	app.GetFunc("/ttl cache=20ms", handler)
+	app.GetFunc("/nocache cache=0s", handler)
+	app.GetFunc("/rate cache=0s ratelimit=1,2", handler)
+
+	get := func(path string) string {
+		var body string
+		err := app.GetRequest(path, NewClientParse(&body))
+		if err != nil {
+			t.Error(path, err)
+		}
+		return body
+	}
+	first := get("/ttl")
+	if get("/ttl") != first {
+		t.Error("cache tag ttl not cached")
+	}
+	time.Sleep(time.Millisecond * 30)
+	if get("/ttl") == first {
+		t.Error("cache tag ttl not expired")
+	}
+	if get("/nocache") == get("/nocache") {
+		t.Error("cache tag 0s cached")
+	}
+
+	app.GetRequest("/rate", NewClientCheckStatus(200))
+	app.GetRequest("/rate", NewClientCheckStatus(200))
+	err := app.GetRequest("/rate", NewClientCheckStatus(429))
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareRateSpeed(*testing.T) {
 	app := NewApp()
 	app.AddMiddleware("global", NewLoggerLevelFunc(func(Context) int { return 4 }))
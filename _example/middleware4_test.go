@@ -1,6 +1,7 @@
 package eudore_test
 
 import (
+	"net/http"
 	"strconv"
 	"testing"
 
@@ -49,6 +50,59 @@ func TestMiddlewareCORS(t *testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareCORSPattern(t *testing.T) {
+	app := NewApp()
+	app.AddMiddleware("global", NewCORSFunc(
+		[]string{"*.example.com", `^https://[a-z]+\.eudore\.cn$`},
+		map[string]string{"Access-Control-Allow-Credentials": "true"},
+		NewOptionRouter(app),
+	))
+	app.GetFunc("/users", HandlerEmpty)
+	app.PostFunc("/users", HandlerEmpty)
+
+	reqs := []struct {
+		method string
+		origin string
+		status int
+	}{
+		{MethodGet, "http://www.example.com", 200},
+		{MethodGet, "https://api.eudore.cn", 200},
+		{MethodGet, "http://example.com", 403},
+		{MethodGet, "http://www.example.org", 403},
+		{MethodGet, "https://api.v2.eudore.cn", 403},
+	}
+	for _, r := range reqs {
+		err := app.NewRequest(r.method, "/users",
+			NewClientHeader(HeaderOrigin, r.origin),
+			NewClientCheckStatus(r.status),
+		)
+		if err != nil {
+			t.Error(r.origin, err)
+		}
+	}
+
+	var header http.Header
+	app.NewRequest(MethodOptions, "/users",
+		NewClientHeader(HeaderOrigin, "http://www.example.com"),
+		NewClientHeader(HeaderAccessControlRequestMethod, MethodPost),
+		NewClientHeader(HeaderAccessControlRequestHeaders, "X-Custom"),
+		NewClientCheckStatus(204),
+		func(w *http.Response) error {
+			header = w.Header
+			return nil
+		},
+	)
+	if header.Get(HeaderAccessControlAllowOrigin) != "http://www.example.com" ||
+		header.Get(HeaderAccessControlAllowCredentials) != "true" ||
+		header.Get(HeaderAccessControlAllowMethods) != "GET, POST" ||
+		header.Get(HeaderAccessControlAllowHeaders) != "X-Custom" {
+		t.Errorf("cors preflight header: %v", header)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareReferer(t *testing.T) {
 	app := NewApp()
 	app.AddMiddleware(NewRefererCheckFunc(map[string]bool{
@@ -17,6 +17,51 @@ import (
 	. "github.com/eudore/eudore/middleware"
 )
 
+func TestMiddlewareAudit(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	app := NewApp()
+	app.AddMiddleware(
+		NewBasicAuthFunc(map[string]string{"eudore": "hello"}),
+		NewAuditFunc(log),
+	)
+	app.AnyFunc("/user", func(ctx Context) {
+		var data map[string]any
+		if err := ctx.Bind(&data); err != nil || data["name"] != "eudore" {
+			ctx.WriteHeader(StatusBadRequest)
+		}
+	})
+
+	auth := http.Header{HeaderAuthorization: {"Basic ZXVkb3JlOmhlbGxv"}}
+	app.PostRequest("/user", auth, NewClientCheckStatus(200),
+		NewClientBodyJSON(map[string]any{"name": "eudore", "password": "123456"}),
+	)
+	app.GetRequest("/user", auth, NewClientCheckStatus(200))
+
+	lines := ring.(interface{ Lines() []string }).Lines()
+	if len(lines) != 1 {
+		t.Fatalf("invalid audit lines: %q", lines)
+	}
+	for _, field := range []string{
+		`"message":"audit"`, `"user":"eudore"`, `"method":"POST"`,
+		`"path":"/user"`, `"status":200`, `"password":"******"`,
+	} {
+		if !strings.Contains(lines[0], field) {
+			t.Errorf("audit line not has %s: %s", field, lines[0])
+		}
+	}
+	if strings.Contains(lines[0], "123456") {
+		t.Errorf("audit line not redacted: %s", lines[0])
+	}
+	if strings.Count(lines[0], `"time":`) != 1 ||
+		!strings.Contains(lines[0], `"duration":`) {
+		t.Errorf("audit line time fields: %s", lines[0])
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareBasicAuth(*testing.T) {
 	app := NewApp()
 	app.AddMiddleware("global", NewBasicAuthFunc(map[string]string{"eudore": "hello"}))
@@ -29,6 +74,54 @@ func TestMiddlewareBasicAuth(*testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareBindDebug(t *testing.T) {
+	type bindUser struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+		Tokens   []struct {
+			Token string `json:"token"`
+		} `json:"tokens"`
+	}
+
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{capture}})
+	app := NewApp()
+	app.AddMiddleware(func(ctx Context) {
+		ctx.SetValue(ContextKeyLogger, log)
+	})
+	app.AddMiddleware(NewBindDebugFunc())
+	app.PostFunc("/user", func(ctx Context) error {
+		var user bindUser
+		return ctx.Bind(&user)
+	})
+	app.PostFunc("/user/name", NewBindDebugFunc("name"), func(ctx Context) error {
+		var user bindUser
+		return ctx.Bind(&user)
+	})
+
+	data := map[string]any{
+		"name": "eudore", "password": "pass123",
+		"tokens": []any{map[string]any{"token": "tk123"}},
+	}
+	app.PostRequest("/user", NewClientBodyJSON(data), NewClientCheckStatus(200))
+	app.PostRequest("/user/name", NewClientBodyJSON(data), NewClientCheckStatus(200))
+	app.CancelFunc()
+	app.Run()
+
+	if len(capture.entries) != 2 {
+		t.Fatal("invalid bind debug log:", capture.entries)
+	}
+	entry := capture.entries[0]
+	if !strings.Contains(entry, `"name":"eudore"`) ||
+		strings.Contains(entry, "pass123") || strings.Contains(entry, "tk123") {
+		t.Error("invalid bind debug redact:", entry)
+	}
+	entry = capture.entries[1]
+	if strings.Contains(entry, "eudore") || !strings.Contains(entry, "pass123") {
+		t.Error("invalid bind debug fields:", entry)
+	}
+}
+
 func TestMiddlewareBodyLimit(*testing.T) {
 	app := NewApp()
 	app.AddMiddleware("global",
@@ -65,6 +158,48 @@ func TestMiddlewareBodyLimit(*testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareCacheControl(t *testing.T) {
+	app := NewApp()
+	static := app.Group("/static")
+	static.AddMiddleware(NewCacheControlFunc("public, max-age=3600"))
+	static.GetFunc("/index.js", func(ctx Context) {
+		ctx.WriteString("index")
+	})
+	static.GetFunc("/empty", func(ctx Context) {})
+	static.GetFunc("/private", func(ctx Context) {
+		ctx.SetHeader(HeaderCacheControl, "private")
+		ctx.WriteString("private")
+	})
+	api := app.Group("/api")
+	api.AddMiddleware(NewCacheControlFunc("no-store"))
+	api.GetFunc("/user", func(ctx Context) {
+		ctx.WriteHeader(StatusCreated)
+	})
+
+	checkHeader := func(val string) func(*http.Response) error {
+		return func(w *http.Response) error {
+			if w.Header.Get(HeaderCacheControl) != val {
+				return fmt.Errorf("invalid cache-control: %q", w.Header.Get(HeaderCacheControl))
+			}
+			return nil
+		}
+	}
+	for path, val := range map[string]string{
+		"/static/index.js": "public, max-age=3600",
+		"/static/empty":    "public, max-age=3600",
+		"/static/private":  "private",
+		"/api/user":        "no-store",
+	} {
+		err := app.GetRequest(path, checkHeader(val))
+		if err != nil {
+			t.Error(path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareContextWrap(*testing.T) {
 	app := NewApp()
 	app.AddMiddleware(NewContextWrapperFunc(newContextParams))
@@ -103,6 +238,138 @@ func (ctx contextParams) GetParam(key string) string {
 	return "none"
 }
 
+func TestMiddlewareDeprecation(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	app := NewApp()
+	sunset := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	v1 := app.Group("/v1")
+	v1.AddMiddleware(
+		NewLoggerGroupFunc("/v1", log),
+		NewDeprecationFunc(sunset, "https://example.com/v2",
+			NewOptionDeprecationWarning(),
+		),
+	)
+	v1.AnyFunc("/user", HandlerEmpty)
+	app.AnyFunc("/v2/user", HandlerEmpty)
+
+	err := app.GetRequest("/v1/user",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			if w.Header.Get(HeaderDeprecation) != "true" ||
+				w.Header.Get(HeaderSunset) != "Wed, 02 Jan 2030 03:04:05 GMT" ||
+				w.Header.Get(HeaderLink) != `<https://example.com/v2>; rel="sunset"` {
+				return fmt.Errorf("invalid deprecation headers: %v", w.Header)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/v2/user",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			if w.Header.Get(HeaderDeprecation) != "" {
+				return fmt.Errorf("invalid deprecation headers: %v", w.Header)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var warning bool
+	for _, line := range ring.(interface{ Lines() []string }).Lines() {
+		if strings.Contains(line, `"level":"WARNING"`) &&
+			strings.Contains(line, "deprecated route GET /v1/user") {
+			warning = true
+		}
+	}
+	if !warning {
+		t.Error("deprecated route warning not logged")
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareHandlerDebug(t *testing.T) {
+	app := NewApp()
+	app.AddMiddleware(NewHandlerDebugFunc())
+	app.AddMiddleware(NewRequestIDFunc(nil))
+	app.GetFunc("/chain", NewBodySizeFunc(), HandlerFunc(func(ctx Context) {
+		ctx.WriteString("chain")
+	}))
+
+	err := app.GetRequest("/chain",
+		NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			chain := w.Header.Get(HeaderXEudoreHandlers)
+			want := []string{
+				"middleware.NewRequestIDFunc",
+				"middleware.NewBodySizeFunc",
+				"TestMiddlewareHandlerDebug",
+			}
+			pos := -1
+			for _, name := range want {
+				index := strings.Index(chain, name)
+				if index <= pos {
+					return fmt.Errorf("invalid handlers chain: %s", chain)
+				}
+				pos = index
+			}
+			if strings.Contains(chain, "NewHandlerDebugFunc") {
+				return fmt.Errorf("handlers chain contains itself: %s", chain)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareGoroutineDump(t *testing.T) {
+	ring := NewLoggerWriterRing(20)
+	app := NewApp()
+	app.SetValue(ContextKeyLogger, NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{ring},
+	}))
+	app.SetValue(ContextKeyContextPool, NewContextBasePool(app))
+	app.GetFunc("/goroutine", NewHandlerGoroutineDump())
+
+	err := app.GetRequest("/goroutine",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("goroutine "),
+		NewClientCheckBody("middleware.NewHandlerGoroutineDump"),
+		NewClientCheckBody("TestMiddlewareGoroutineDump"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = app.GetRequest("/goroutine?log=1",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("written to logger"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	lines := strings.Join(ring.(interface{ Lines() []string }).Lines(), "\n")
+	if !strings.Contains(lines, "goroutine dump") ||
+		!strings.Contains(lines, "TestMiddlewareGoroutineDump") {
+		t.Errorf("goroutine dump not logged: %s", lines)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareHeader(*testing.T) {
 	app := NewApp()
 	app.AddMiddleware("global", NewHeaderAddSecureFunc(http.Header{"Server": {"eudore"}}))
@@ -129,6 +396,238 @@ func TestMiddlewareHeader(*testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareHeaderSecure(t *testing.T) {
+	app := NewApp()
+	app.AnyFunc("/default", NewHeaderAddSecureFunc(nil), HandlerEmpty)
+	app.AnyFunc("/custom", NewHeaderAddSecureFunc(nil, NewOptionHeaderSecure(
+		HeaderSecureConfig{
+			ContentSecurityPolicy: "default-src 'self'",
+			FrameOptions:          "DENY",
+			XSSProtection:         "-",
+			HSTSMaxAge:            31536000,
+			HSTSIncludeSubDomains: true,
+		},
+	)), HandlerEmpty)
+
+	var header http.Header
+	getHeader := func(w *http.Response) error {
+		header = w.Header
+		return nil
+	}
+	app.GetRequest("/default", getHeader)
+	if header.Get(HeaderStrictTransportSecurity) != "" ||
+		header.Get(HeaderXFrameOptions) != "SAMEORIGIN" {
+		t.Fatalf("default secure header error: %v", header)
+	}
+
+	app.GetRequest("/custom", getHeader)
+	if header.Get(HeaderContentSecurityPolicy) != "default-src 'self'" ||
+		header.Get(HeaderXFrameOptions) != "DENY" ||
+		header.Get(HeaderXXSSProtection) != "" ||
+		header.Get(HeaderXContentTypeOptions) != "nosniff" ||
+		header.Get(HeaderStrictTransportSecurity) != "max-age=31536000; includeSubDomains" {
+		t.Fatalf("custom secure header error: %v", header)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareLoggerGroup(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	app := NewApp()
+	app.AddMiddleware("global", NewLoggerFunc(log))
+	api := app.Group("/api/v1")
+	api.AddMiddleware(NewLoggerGroupFunc("/api/v1", log.WithField("team", "core")))
+	api.GetFunc("/user", func(ctx Context) {
+		ctx.Info("group handler")
+	})
+	app.GetFunc("/index", func(ctx Context) {
+		log.Info("index handler")
+	})
+
+	app.GetRequest("/api/v1/user", NewClientCheckStatus(200))
+	app.GetRequest("/index", NewClientCheckStatus(200))
+
+	lines := ring.(interface{ Lines() []string }).Lines()
+	if len(lines) != 4 {
+		t.Fatalf("invalid logger lines: %q", lines)
+	}
+	for i, line := range lines {
+		hasGroup := strings.Contains(line, `"group":"/api/v1"`) &&
+			strings.Contains(line, `"team":"core"`)
+		if hasGroup != (i < 2) {
+			t.Errorf("invalid group fields %d: %s", i, line)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareLoggerSkip(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	app := NewApp()
+	app.AddMiddleware("global", NewLoggerFunc(log,
+		"skip:/health", "skip:/admin/*", "debug:/metrics",
+	))
+	app.AnyFunc("/*", HandlerEmpty)
+
+	app.GetRequest("/health")
+	app.GetRequest("/admin/index.js")
+	app.GetRequest("/metrics")
+	app.GetRequest("/index")
+	app.GetRequest("/admin")
+
+	lines := ring.(interface{ Lines() []string }).Lines()
+	if len(lines) != 3 {
+		t.Fatalf("invalid logger lines: %q", lines)
+	}
+	for i, path := range []string{"/metrics", "/index", "/admin"} {
+		if !strings.Contains(lines[i], `"path":"`+path+`"`) {
+			t.Errorf("invalid logger path %s: %s", path, lines[i])
+		}
+	}
+	if !strings.Contains(lines[0], `"level":"DEBUG"`) ||
+		!strings.Contains(lines[1], `"level":"INFO"`) {
+		t.Errorf("invalid logger level: %q", lines)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareLoggerSample(t *testing.T) {
+	ring := NewLoggerWriterRing(100)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	app := NewApp()
+	app.AddMiddleware("global", NewLoggerFunc(log,
+		"sample:2xx=0.1", "sample:3xx=0", "sample:9xx=1", "response:X-Request-Id",
+	))
+	app.GetFunc("/ok", HandlerEmpty)
+	app.GetFunc("/redirect", func(ctx Context) {
+		ctx.WriteHeader(StatusNotModified)
+	})
+	app.GetFunc("/error", func(ctx Context) {
+		ctx.WriteHeader(StatusInternalServerError)
+	})
+
+	for i := 0; i < 50; i++ {
+		app.GetRequest("/ok")
+		app.GetRequest("/redirect")
+		if i%10 == 0 {
+			app.GetRequest("/error")
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, line := range ring.(interface{ Lines() []string }).Lines() {
+		for _, path := range []string{"/ok", "/redirect", "/error"} {
+			if strings.Contains(line, `"path":"`+path+`"`) {
+				counts[path]++
+			}
+		}
+	}
+	if counts["/ok"] != 5 || counts["/redirect"] != 0 || counts["/error"] != 5 {
+		t.Errorf("invalid sample counts: %v", counts)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareLoggerRoute(t *testing.T) {
+	ring := NewLoggerWriterRing(10)
+	log := NewLogger(&LoggerConfig{Handlers: []LoggerHandler{ring}})
+	app := NewApp()
+	app.AddMiddleware("global", NewLoggerFunc(log))
+	app.GetFunc("/users/:id", HandlerEmpty)
+	app.GetFunc("/files/*path", HandlerEmpty)
+
+	app.GetRequest("/users/42")
+	app.GetRequest("/files/css/app.css")
+	app.PutRequest("/users/42")
+	app.GetRequest("/groups/1")
+
+	lines := ring.(interface{ Lines() []string }).Lines()
+	fields := [][2]string{
+		{"/users/42", "/users/:id"},
+		{"/files/css/app.css", "/files/*path"},
+		{"/users/42", "/users/:id"},
+		{"/groups/1", ""},
+	}
+	if len(lines) != len(fields) {
+		t.Fatalf("invalid logger lines: %q", lines)
+	}
+	for i, field := range fields {
+		if !strings.Contains(lines[i], `"path":"`+field[0]+`"`) ||
+			!strings.Contains(lines[i], `"route":"`+field[1]+`"`) {
+			t.Errorf("invalid logger route %v: %s", field, lines[i])
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareOptimisticLock(t *testing.T) {
+	version := 1
+	app := NewApp()
+	app.AddMiddleware(NewOptimisticLockFunc(func(ctx Context) string {
+		if ctx.GetParam("id") != "1" {
+			return ""
+		}
+		return fmt.Sprintf("v%d", version)
+	}))
+	app.GetFunc("/user/:id", func(ctx Context) {
+		ctx.SetETag(fmt.Sprintf("v%d", version))
+	})
+	app.AnyFunc("/user/:id", func(ctx Context) {
+		version++
+		ctx.SetETag(fmt.Sprintf("v%d", version))
+	})
+
+	var etag string
+	app.GetRequest("/user/1", NewClientCheckStatus(200),
+		func(w *http.Response) error {
+			etag = w.Header.Get(HeaderETag)
+			return nil
+		},
+	)
+	reqs := []struct {
+		method string
+		path   string
+		match  string
+		status int
+	}{
+		{"PUT", "/user/1", etag, 200},
+		{"PATCH", "/user/1", etag, 412},
+		{"PATCH", "/user/1", `"x", "v2"`, 200},
+		{"PUT", "/user/1", "*", 200},
+		{"PUT", "/user/1", `W/"v4"`, 412},
+		{"PUT", "/user/2", "*", 412},
+		{"PUT", "/user/1", "", 200},
+		{"POST", "/user/1", `"v0"`, 200},
+	}
+	for _, r := range reqs {
+		err := app.NewRequest(r.method, r.path,
+			http.Header{HeaderIfMatch: {r.match}},
+			NewClientCheckStatus(r.status),
+		)
+		if err != nil {
+			t.Error(r.method, r.match, err)
+		}
+	}
+	if etag != `"v1"` || version != 6 {
+		t.Errorf("invalid etag %s version %d", etag, version)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareRecover(*testing.T) {
 	app := NewApp()
 	app.AddMiddleware("global",
@@ -162,6 +661,141 @@ func TestMiddlewareRecover(*testing.T) {
 	app.Run()
 }
 
+func TestMiddlewareRecoverCounter(t *testing.T) {
+	counter := make(map[string]int)
+	app := NewApp()
+	app.AddMiddleware("global",
+		NewRecoveryFunc(NewOptionRecoveryCounter(func(route string) {
+			counter[route]++
+		})),
+	)
+	app.AnyFunc("/panic/:id", func(ctx Context) {
+		panic("test error")
+	})
+	app.AnyFunc("/ok", func(ctx Context) {})
+
+	app.GetRequest("/panic/1", NewClientCheckStatus(500))
+	app.GetRequest("/panic/2", NewClientCheckStatus(500))
+	app.GetRequest("/ok", NewClientCheckStatus(200))
+
+	app.CancelFunc()
+	app.Run()
+	if counter["/panic/:id"] != 2 || len(counter) != 1 {
+		t.Fatalf("recovery counter error: %v", counter)
+	}
+}
+
+func TestMiddlewareRecoverFatal(t *testing.T) {
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	panicError := func(ctx Context) {
+		panic("test error")
+	}
+	app.GetFunc("/panic", NewRecoveryFunc(NewOptionRecoveryFatal(false)), panicError)
+	app.GetFunc("/stack", NewRecoveryFunc(NewOptionRecoveryFatal(true)), panicError)
+	app.GetFunc("/status", NewRecoveryFunc(NewOptionRecoveryFatal(false)),
+		func(ctx Context) {
+			panic(NewErrorWithStatus(fmt.Errorf("gone"), StatusGone))
+		},
+	)
+
+	reqs := []struct {
+		path   string
+		status int
+		error  string
+		stack  bool
+	}{
+		{"/panic", 500, "test error", false},
+		{"/stack", 500, "test error", true},
+		{"/status", 410, "gone", false},
+	}
+	for _, req := range reqs {
+		var msg map[string]any
+		err := app.GetRequest(req.path,
+			NewClientCheckStatus(req.status),
+			NewClientParse(&msg),
+		)
+		if err != nil {
+			t.Error(req.path, err)
+			continue
+		}
+		_, stack := msg["message"].([]any)
+		if msg["status"] != float64(req.status) || msg["error"] != req.error ||
+			msg["path"] != req.path || stack != req.stack {
+			t.Errorf("invalid error envelope %s: %v", req.path, msg)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareRequestLimit(t *testing.T) {
+	app := NewApp()
+	app.AddMiddleware(NewRequestLimitFunc(64, 16, 1024))
+	app.AnyFunc("/*", HandlerEmpty)
+
+	many := http.Header{}
+	for i := 0; i < 20; i++ {
+		many.Add(fmt.Sprintf("X-Many-%d", i), "eudore")
+	}
+	reqs := []struct {
+		path   string
+		header http.Header
+		status int
+		body   string
+	}{
+		{"/index", http.Header{}, 200, ""},
+		{"/" + strings.Repeat("a", 64), http.Header{}, 414, "uri limit 64 bytes"},
+		{"/index", many, 431, "header limit count 16"},
+		{"/index", http.Header{"X-Large": {strings.Repeat("a", 1024)}}, 431, "header limit size 1024 bytes"},
+	}
+	for _, r := range reqs {
+		err := app.GetRequest(r.path, r.header,
+			NewClientCheckStatus(r.status), NewClientCheckBody(r.body),
+		)
+		if err != nil {
+			t.Error(r.path, err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestMiddlewareRequireHeaders(t *testing.T) {
+	app := NewApp()
+	api := app.Group("/api")
+	api.AddMiddleware(NewRequireHeadersFunc("x-tenant-id", HeaderXRequestID))
+	api.AnyFunc("/*", HandlerEmpty)
+	app.AnyFunc("/public", HandlerEmpty)
+
+	errs := []error{
+		app.GetRequest("/api/user",
+			http.Header{"X-Tenant-Id": {"1"}, HeaderXRequestID: {"2"}},
+			NewClientCheckStatus(200),
+		),
+		app.GetRequest("/api/user",
+			http.Header{"X-Tenant-Id": {"1"}},
+			NewClientCheckStatus(400),
+			NewClientCheckBody(HeaderXRequestID),
+		),
+		app.GetRequest("/api/user",
+			NewClientCheckStatus(400),
+			NewClientCheckBody("X-Tenant-Id, "+HeaderXRequestID),
+		),
+		app.GetRequest("/public", NewClientCheckStatus(200)),
+	}
+	for _, err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
 func TestMiddlewareRoutes(*testing.T) {
 	hend := func(ctx Context) { ctx.End() }
 	h500 := func(ctx Context) { ctx.WriteHeader(500) }
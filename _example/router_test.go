@@ -3,7 +3,9 @@ package eudore_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	. "github.com/eudore/eudore"
@@ -56,6 +58,40 @@ func TestRouterStdAdd(t *testing.T) {
 	r.Match(MethodOptions, "/api/allow", &Params{"route", ""})
 }
 
+func TestRouterPath(t *testing.T) {
+	base := NewRoutePath("/file/")
+	route := base.Static("data").WithParam("id", "num").WithWildcard("path")
+	if route.String() != "/file/data/:id|num/*path" {
+		t.Fatalf("invalid route path: %s", route)
+	}
+	if base.Static("/index").WithWildcard("").String() != "/file/index/*" {
+		t.Fatalf("invalid route path: %s", base)
+	}
+	id, path := route.Param("id"), route.Param("path")
+
+	app := NewApp()
+	app.GetFunc(route.String(), func(ctx Context) {
+		fmt.Fprintf(ctx, "%s %s", id.Get(ctx), path.Get(ctx))
+	})
+	err := app.GetRequest("/file/data/12/docs/index.md",
+		NewClientCheckStatus(200),
+		NewClientCheckBody("12 docs/index.md"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	app.GetRequest("/file/data/name/index.md", NewClientCheckStatus(404))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("undefined param not panic")
+		}
+		app.CancelFunc()
+		app.Run()
+	}()
+	route.Param("name")
+}
+
 func TestRouterError(t *testing.T) {
 	var h HandlerFunc
 	r := NewRouter(nil)
@@ -94,6 +130,33 @@ func TestRouterMiddleware(t *testing.T) {
 	apiv1.AnyFunc("/users", HandlerEmpty)
 }
 
+func TestRouterMiddlewarePriority(t *testing.T) {
+	var order []string
+	newMiddleware := func(name string) HandlerFunc {
+		return func(Context) {
+			order = append(order, name)
+		}
+	}
+
+	app := NewApp()
+	app.AddMiddleware("/api", newMiddleware("api"))
+	app.AddMiddleware(" priority=auth", newMiddleware("auth"))
+	app.AddMiddleware(" priority=recovery", newMiddleware("recovery"))
+	app.AddMiddleware(" priority=logger", newMiddleware("logger"))
+	api := app.Group("/api")
+	api.AddMiddleware(" priority=5", newMiddleware("first"))
+	api.AddMiddleware(newMiddleware("group"))
+	api.GetFunc("/users", newMiddleware("handler"))
+
+	app.GetRequest("/api/users", NewClientCheckStatus(200))
+	app.CancelFunc()
+	app.Run()
+
+	if fmt.Sprint(order) != "[first logger recovery auth api group handler]" {
+		t.Fatalf("middleware order error: %v", order)
+	}
+}
+
 func TestRouterCoreHost(t *testing.T) {
 	echoHandleHost := func(ctx Context) {
 		ctx.WriteString(ctx.GetParam("route-host"))
@@ -148,3 +211,80 @@ func TestRouterCoreHost(t *testing.T) {
 	r.(interface{ Mount(context.Context) }).Mount(context.Background())
 	r.(interface{ Unmount(context.Context) }).Unmount(context.Background())
 }
+
+func TestRouterHandlerExtendError(t *testing.T) {
+	defer func(kind string) { DefaultRouterLoggerKind = kind }(DefaultRouterLoggerKind)
+	DefaultRouterLoggerKind = "all"
+
+	capture := &loggerCapture{}
+	log := NewLogger(&LoggerConfig{
+		Handlers: []LoggerHandler{capture},
+	})
+
+	r := NewRouter(nil)
+	r.(interface{ Mount(context.Context) }).Mount(
+		context.WithValue(context.Background(), ContextKeyLogger, log),
+	)
+	err := r.AddHandlerExtend(999)
+	if err == nil || !strings.Contains(err.Error(), "current type is int") {
+		t.Fatal("invalid extend error:", err)
+	}
+	if len(capture.entries) != 1 ||
+		!strings.Contains(capture.entries[0], `"level":"ERROR"`) ||
+		!strings.Contains(capture.entries[0], "current type is int") {
+		t.Fatal("invalid extend log:", capture.entries)
+	}
+}
+
+func TestRouterSuggestion(t *testing.T) {
+	defer func(b bool) { DefaultRouterSuggestion = b }(DefaultRouterSuggestion)
+	DefaultRouterSuggestion = true
+
+	app := NewApp()
+	app.SetValue(ContextKeyRender, HandlerDataRenderJSON)
+	app.GetFunc("/api/v1/users/:id", HandlerEmpty)
+	app.GetFunc("/api/v1/groups/:id", HandlerEmpty)
+	app.GetFunc("/api/v1/files/*", HandlerEmpty)
+
+	err := app.GetRequest("/api/v1/user/1",
+		NewClientCheckStatus(StatusNotFound),
+		NewClientCheckBody("did you mean: "),
+		NewClientCheckBody("/api/v1/users/:id"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/api/v2/groups/1",
+		NewClientCheckStatus(StatusNotFound),
+		NewClientCheckBody("/api/v1/groups/:id"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	err = app.GetRequest("/api/v2/user/1",
+		NewClientCheckStatus(StatusNotFound),
+		func(w *http.Response) error {
+			body, _ := io.ReadAll(w.Body)
+			if strings.Contains(string(body), "did you mean") {
+				return fmt.Errorf("invalid suggestion: %s", body)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.AddHandler("404", "", NewHandlerRouter404(nil))
+	err = app.GetRequest("/api/v1/group/1",
+		NewClientCheckStatus(StatusNotFound),
+		NewClientCheckBody(`"suggest": `),
+		NewClientCheckBody("/api/v1/groups/:id"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
@@ -3,6 +3,11 @@ package eudore_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -224,6 +229,284 @@ func TestUtilGetSetValue(t *testing.T) {
 	get(data, "index")
 }
 
+func TestUtilGetAnyDiff(t *testing.T) {
+	type Database struct {
+		Host string `alias:"host"`
+		Port int    `alias:"port"`
+	}
+	type config struct {
+		Name     string            `alias:"name"`
+		Database *Database         `alias:"database"`
+		Labels   map[string]string `alias:"labels"`
+		Hosts    []string          `alias:"hosts"`
+		Start    time.Time         `alias:"start"`
+		Any      any               `alias:"any"`
+		secret   string
+	}
+
+	now := time.Now()
+	a := &config{
+		Name:     "eudore",
+		Database: &Database{Host: "127.0.0.1", Port: 3306},
+		Labels:   map[string]string{"env": "dev", "zone": "a"},
+		Hosts:    []string{"a", "b", "c"},
+		Start:    now,
+		Any:      map[string]any{"level": 1},
+		secret:   "a",
+	}
+	b := &config{
+		Name:     "eudore",
+		Database: &Database{Host: "127.0.0.1", Port: 5432},
+		Labels:   map[string]string{"env": "prod", "app": "api"},
+		Hosts:    []string{"a", "b"},
+		Start:    now.Add(time.Second),
+		Any:      map[string]any{"level": 1},
+		secret:   "b",
+	}
+
+	diff := GetAnyDiff(a, b)
+	want := map[string]any{
+		"database.port": 5432,
+		"labels.env":    "prod",
+		"labels.zone":   nil,
+		"labels.app":    "api",
+		"hosts.2":       nil,
+		"start":         b.Start,
+	}
+	if len(diff) != len(want) {
+		t.Errorf("diff length %d != %d: %v", len(diff), len(want), diff)
+	}
+	for key, val := range want {
+		if diff[key] != val {
+			t.Errorf("diff %s %v != %v", key, diff[key], val)
+		}
+		if val != nil && GetAnyByPath(b, key) != val {
+			t.Errorf("diff path %s not found by GetAnyByPath", key)
+		}
+	}
+
+	if diff := GetAnyDiff(a, a); len(diff) != 0 {
+		t.Errorf("diff the same object: %v", diff)
+	}
+	diff = GetAnyDiff(&config{}, &config{Database: &Database{}})
+	if len(diff) != 1 || diff["database"] == nil {
+		t.Errorf("diff nil pointer: %v", diff)
+	}
+	diff = GetAnyDiff(1, "1")
+	if len(diff) != 1 || diff[""] != "1" {
+		t.Errorf("diff type: %v", diff)
+	}
+}
+
+func TestUtilMarshalValues(t *testing.T) {
+	type Item struct {
+		ID   int    `alias:"id"`
+		Name string `alias:"name"`
+	}
+	type Inner struct {
+		Name string `alias:"name"`
+		Port int    `alias:"port"`
+	}
+	type Query struct {
+		Tags    []string          `alias:"tags"`
+		IDs     []int             `alias:"ids"`
+		Enable  bool              `alias:"enable"`
+		Timeout time.Duration     `alias:"timeout"`
+		Start   time.Time         `alias:"start"`
+		Inner   Inner             `alias:"inner"`
+		Items   []Item            `alias:"items"`
+		Labels  map[string]string `alias:"labels"`
+		Next    *Inner            `alias:"next"`
+		private int
+	}
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	query := Query{
+		Tags:    []string{"a", "b"},
+		IDs:     []int{1, 2},
+		Enable:  true,
+		Timeout: 5 * time.Second,
+		Start:   start,
+		Inner:   Inner{"eudore", 8080},
+		Items:   []Item{{1, "x"}, {2, "y"}},
+		Labels:  map[string]string{"env": "dev"},
+	}
+	values := MarshalValues(&query, nil)
+	want := url.Values{
+		"tags":         {"a", "b"},
+		"ids":          {"1", "2"},
+		"enable":       {"true"},
+		"timeout":      {"5s"},
+		"start":        {"2024-01-02T03:04:05Z"},
+		"inner.name":   {"eudore"},
+		"inner.port":   {"8080"},
+		"items.0.id":   {"1"},
+		"items.0.name": {"x"},
+		"items.1.id":   {"2"},
+		"items.1.name": {"y"},
+		"labels.env":   {"dev"},
+	}
+	if values.Encode() != want.Encode() {
+		t.Errorf("invalid values:\n%s\n%s", values.Encode(), want.Encode())
+	}
+
+	var data Query
+	err := SetFromValues(&data, values, nil)
+	if err != nil || strings.Join(data.Tags, ",") != "a,b" ||
+		data.Inner != query.Inner || !data.Start.Equal(start) {
+		t.Errorf("invalid unmarshal: %#v %v", data, err)
+	}
+	t.Log(MarshalValues(nil, nil), MarshalValues(1, nil))
+}
+
+func TestUtilSetFromValues(t *testing.T) {
+	type Inner struct {
+		Name string `alias:"name"`
+		Port int    `alias:"port"`
+	}
+	type Query struct {
+		Tags    []string      `alias:"tags"`
+		IDs     []int         `alias:"ids"`
+		Size    int           `alias:"size"`
+		Enable  bool          `alias:"enable"`
+		Timeout time.Duration `alias:"timeout"`
+		Inner   Inner         `alias:"inner"`
+	}
+
+	values, _ := url.ParseQuery("tags=a&tags=b&ids=1&ids=2&ids=3&size=10" +
+		"&enable=yes&timeout=5s&inner.name=eudore&inner.port=8080&unknown=1")
+	var query Query
+	err := SetFromValues(&query, values, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(query.Tags, ",") != "a,b" || len(query.IDs) != 3 ||
+		query.IDs[2] != 3 || query.Size != 10 || !query.Enable ||
+		query.Timeout != 5*time.Second || query.Inner.Name != "eudore" ||
+		query.Inner.Port != 8080 {
+		t.Errorf("invalid query: %#v", query)
+	}
+
+	data := make(map[string]any)
+	err = SetFromValues(&data, url.Values{"a.b": {"1"}}, nil)
+	if err != nil || GetAnyByPath(data, "a.b") != "1" {
+		t.Errorf("invalid map: %v %v", data, err)
+	}
+
+	err = SetFromValues(&query, url.Values{"size": {"x"}}, nil)
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("invalid error: %v", err)
+	}
+	t.Log(SetFromValues(nil, values, nil))
+	t.Log(SetFromValues(query, values, nil))
+	t.Log(SetFromValues(&query.Tags, values, nil))
+}
+
+func TestUtilSetNumberRange(t *testing.T) {
+	type config struct {
+		Int8       int8       `alias:"int8"`
+		Uint8      uint8      `alias:"uint8"`
+		Float32    float32    `alias:"float32"`
+		Complex128 complex128 `alias:"complex128"`
+	}
+	data := new(config)
+	for _, key := range []string{"int8", "uint8", "float32"} {
+		err := SetAnyByPath(data, key, "1e300")
+		if err == nil || !strings.Contains(err.Error(), "path '"+key+"'") ||
+			!strings.Contains(err.Error(), "parse '1e300' to "+key) {
+			t.Errorf("set %s error: %v", key, err)
+		}
+	}
+	for key, val := range map[string]string{
+		"int8": "300", "uint8": "-1", "float32": "1e40",
+	} {
+		err := SetAnyByPath(data, key, val)
+		if !errors.Is(err, strconv.ErrRange) && !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("set %s %s error: %v", key, val, err)
+		}
+	}
+	err := SetAnyByPath(data, "complex128", "(1+2i)")
+	if err != nil || data.Complex128 != complex(1, 2) {
+		t.Error(err, data.Complex128)
+	}
+
+	app := NewApp()
+	app.GetFunc("/bind", func(ctx Context) error {
+		return ctx.Bind(data)
+	})
+	err = app.GetRequest("/bind?int8=300",
+		NewClientCheckStatus(400),
+		NewClientCheckBody("parse '300' to int8: value out of range"),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+
+	app.CancelFunc()
+	app.Run()
+}
+
+func TestUtilGetValueAll(t *testing.T) {
+	type config struct {
+		Name string `alias:"name"`
+		ano  string `alias:"ano"`
+	}
+	data := &config{Name: "eudore", ano: "private"}
+
+	val, err := GetAnyByPathWithValue(data, "ano", nil, true)
+	if err != nil || val.String() != "private" {
+		t.Errorf("get unexported field with all: %v %v", val, err)
+	}
+	_, err = GetAnyByPathWithValue(data, "ano", nil, false)
+	if err == nil || !strings.Contains(err.Error(), "is unexported") {
+		t.Errorf("get unexported field without all: %v", err)
+	}
+
+	ano, err := GetAnyByPathWithTag(data, "ano", nil, true)
+	if err != nil || ano != "private" {
+		t.Errorf("get unexported field with all: %v %v", ano, err)
+	}
+	_, err = GetAnyByPathWithTag(data, "ano", nil, false)
+	if err == nil {
+		t.Error("get unexported field without all not error")
+	}
+	if GetAnyByPath(data, "ano") != nil || GetAnyByPath(data, "name") != "eudore" {
+		t.Error("get field by path error")
+	}
+}
+
+func TestUtilValueErrorPath(t *testing.T) {
+	type config struct {
+		Name  string            `alias:"name"`
+		Int   int               `alias:"int"`
+		Map   map[string]string `alias:"map"`
+		Slice []int             `alias:"slice"`
+	}
+	data := &config{Slice: []int{1}}
+
+	for _, req := range [][2]string{
+		{"name.num", "value get path 'name.num' error: string type string not found field 'num'"},
+		{"null", "value get path 'null' error"},
+		{"slice.0.x", "value get path 'slice.0.x' error"},
+		{"slice.9", "value get path 'slice.9' error"},
+	} {
+		_, err := GetAnyByPathWithTag(data, req[0], nil, false)
+		if err == nil || !strings.Contains(err.Error(), req[1]) {
+			t.Errorf("get %s error: %v", req[0], err)
+		}
+	}
+	for _, req := range [][2]string{
+		{"int.x", "value set path 'int.x' error"},
+		{"int", "value set path 'int' error"},
+		{"map.key.x", "value set path 'map.key.x' error"},
+	} {
+		err := SetAnyByPath(data, req[0], "x")
+		if err == nil || !strings.Contains(err.Error(), req[1]) {
+			t.Errorf("set %s error: %v", req[0], err)
+		}
+	}
+}
+
 func TestUtilSetWithValue(t *testing.T) {
 	type time2 time.Time
 	type config struct {
@@ -304,3 +587,83 @@ func TestUtilSetWithValue(t *testing.T) {
 	SetAnyByPathWithTag(c, "name", "eudore", nil, false)
 	GetAnyByPathWithTag(c, "name", nil, false)
 }
+
+func TestUtilSetWithNetURL(t *testing.T) {
+	type config struct {
+		Listen net.IP    `alias:"listen"`
+		Block  net.IPNet `alias:"block"`
+		Proxy  url.URL   `alias:"proxy"`
+		Server *url.URL  `alias:"server"`
+	}
+
+	data := new(config)
+	for _, kv := range [][2]string{
+		{"listen", " 127.0.0.1"},
+		{"block", "10.0.0.0/8"},
+		{"proxy", "http://127.0.0.1:8080/proxy"},
+		{"server", "https://example.com/api?debug=true"},
+	} {
+		err := SetAnyByPath(data, kv[0], kv[1])
+		if err != nil {
+			t.Fatal(kv[0], err)
+		}
+	}
+	if !data.Listen.Equal(net.IPv4(127, 0, 0, 1)) ||
+		data.Block.String() != "10.0.0.0/8" ||
+		data.Proxy.Host != "127.0.0.1:8080" ||
+		data.Server == nil || data.Server.Query().Get("debug") != "true" {
+		t.Fatalf("invalid set value: %#v", data)
+	}
+
+	for _, kv := range [][2]string{
+		{"listen", "127.0.0.x"},
+		{"block", "10.0.0.0"},
+		{"proxy", "http://[::1"},
+	} {
+		if SetAnyByPath(data, kv[0], kv[1]) == nil {
+			t.Error(kv[0], "invalid value not error")
+		}
+	}
+}
+
+func TestUtilScheduleNext(t *testing.T) {
+	// 2024-01-15 is Monday.
+	now := time.Date(2024, 1, 15, 10, 30, 20, 0, time.UTC)
+	date := func(m time.Month, d, h, min int) time.Time {
+		return time.Date(2024, m, d, h, min, 0, 0, time.UTC)
+	}
+	for _, data := range []struct {
+		spec string
+		now  time.Time
+		next time.Time
+	}{
+		{"@every 1m", now, now.Add(time.Minute)},
+		{"30s", now, now.Add(time.Second * 30)},
+		{"*/5 * * * *", now, date(1, 15, 10, 35)},
+		{"0 9-18/3 * * 1-5", now, date(1, 15, 12, 0)},
+		{"0 9-18/3 * * 1-5", date(1, 19, 18, 0), date(1, 22, 9, 0)},
+		{"0 0 * * 0", now, date(1, 21, 0, 0)},
+		// day-of-month or day-of-week matches.
+		{"0 0 1 * 1", now, date(1, 22, 0, 0)},
+		{"0 0 20 * 1", now, date(1, 20, 0, 0)},
+		{"0 0 29 2 *", now, date(2, 29, 0, 0)},
+		{"0 0 29 2 *", date(3, 1, 0, 0), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"59 23 31 12 *", now, date(12, 31, 23, 59)},
+	} {
+		next, err := NewScheduleNext(data.spec)
+		if err != nil {
+			t.Error(data.spec, err)
+			continue
+		}
+		if got := next(data.now); !got.Equal(data.next) {
+			t.Errorf("schedule %q next %s, want %s", data.spec, got, data.next)
+		}
+	}
+
+	for _, spec := range []string{"0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		_, err := NewScheduleNext(spec)
+		if err == nil {
+			t.Errorf("schedule spec %q must be invalid", spec)
+		}
+	}
+}
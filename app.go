@@ -21,6 +21,8 @@ import (
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // App combines [context.Context] [Logger] [Config] [Router] [Client] [Server],
@@ -41,6 +43,8 @@ type App struct {
 	CancelError        error      `alias:"cancelerror"`
 	Mutex              sync.Mutex `alias:"mutex"`
 	Values             []any      `alias:"values"`
+	requests           int64
+	tasks              sync.WaitGroup
 }
 
 // The NewApp() function creates an App object, initializes various components
@@ -67,6 +71,11 @@ func (app *App) Run() error {
 	defer app.SetValue(ContextKeyClient, nil)
 	defer app.SetValue(ContextKeyServer, nil)
 	defer func() {
+		app.WithField(ParamDepth, 2).Infof(
+			"eudore app shutting down, draining %d requests",
+			atomic.LoadInt64(&app.requests),
+		)
+		app.waitTasks()
 		for i := len(app.Values) - 2; i > -1; i -= 2 {
 			app.SetValue(app.Values[i], nil)
 		}
@@ -85,10 +94,14 @@ func (app *App) Run() error {
 // SetValue method sets the specified key value from the App.
 //
 // If the value implements the Mount/Unmount method,
-// this method is automatically called when setting and unsetting.
+// this method is automatically called when setting and unsetting,
+// and outputs the [LoggerDebug] component lifecycle log.
 func (app *App) SetValue(key, val any) {
+	old := app.Value(key)
 	anyMount(app, val)
-	defer anyUnmount(app, app.Value(key))
+	app.loggerComponent(key, val, "mounted")
+	defer app.loggerComponent(key, old, "unmounted")
+	defer anyUnmount(app, old)
 	app.Mutex.Lock()
 	defer app.Mutex.Unlock()
 	switch key {
@@ -174,6 +187,20 @@ func (app *App) Err() error {
 	return app.Context.Err()
 }
 
+func (app *App) loggerComponent(key, val any, action string) {
+	var ok bool
+	switch action {
+	case "mounted":
+		_, ok = val.(interface{ Mount(ctx context.Context) })
+	default:
+		_, ok = val.(interface{ Unmount(ctx context.Context) })
+	}
+	if ok && app.Logger != nil {
+		app.Logger.WithField(ParamDepth, 2).
+			Debugf("eudore app component %v %s", key, action)
+	}
+}
+
 func anyMount(ctx context.Context, i any) {
 	loader, ok := i.(interface{ Mount(ctx context.Context) })
 	if ok {
@@ -210,10 +237,33 @@ func (app *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := pool.Get().(Context)
 	ctx.Reset(w, r)
 	ctx.SetHandlers(-1, app.HandlerFuncs)
+	atomic.AddInt64(&app.requests, 1)
+	defer atomic.AddInt64(&app.requests, -1)
+	if DefaultContextSlowHandler > 0 {
+		timer := app.watchSlowHandler(r, DefaultContextSlowHandler)
+		defer timer.Stop()
+	}
 	ctx.Next()
+	// write the pending status code of the response without body.
+	ctx.Response().WriteStatus(0)
 	pool.Put(ctx)
 }
 
+// The watchSlowHandler method uses a timer to output the [LoggerWarning] log
+// when the request is still running after dura.
+//
+// The timer only reads the [http.Request],
+// because the [Context] is being used by handlers.
+func (app *App) watchSlowHandler(r *http.Request, dura time.Duration,
+) *time.Timer {
+	return time.AfterFunc(dura, func() {
+		app.WithFields(
+			[]string{"method", "path", "duration"},
+			[]any{r.Method, r.URL.Path, dura.String()},
+		).Warning(ErrAppSlowHandler)
+	})
+}
+
 // serveContext Implement the request context function.
 func (app *App) serveContext(ctx Context) {
 	ctx.SetHandlers(-1, app.Router.Match(ctx.Method(), ctx.Path(), ctx.Params()))
@@ -292,17 +342,150 @@ func (app *App) ListenTLS(addr, cert, key string) error {
 func (app *App) Serve(ln net.Listener) {
 	srv := app.Server
 	go func() {
+		defer app.Recover()
 		app.SetValue(ContextKeyError, srv.Serve(ln))
 	}()
 }
 
+// The Go method starts a background task that is tracked by the App,
+// fn uses the App as [context.Context] to receive shutdown cancellation.
+//
+// The panic of fn is recovered and outputs the [LoggerError] log with the
+// stack, does not shutdown the App.
+// If fatal is true, outputs the [LoggerFatal] log like [App.Recover].
+//
+// The Run method waits for all tasks to exit before unmounting components,
+// up to [DefaultServerShutdownWait].
+func (app *App) Go(fn func(ctx context.Context), fatal ...bool) {
+	app.tasks.Add(1)
+	go func() {
+		defer app.tasks.Done()
+		defer func() {
+			r := recover()
+			if r != nil {
+				recoverPanic(app, r, len(fatal) > 0 && fatal[0])
+			}
+		}()
+		fn(app)
+	}()
+}
+
+// The Schedule method uses [App.Go] to run fn periodically until the
+// App is shutdown, the spec is '@every 1m', a duration or 5 fields cron
+// such as '*/5 * * * *'.
+//
+// Each run outputs the duration log, and the panic of fn is recovered
+// and outputs the [LoggerError] log with the stack.
+//
+// The spec format refer: [NewScheduleNext].
+func (app *App) Schedule(spec string, fn func(ctx context.Context)) error {
+	next, err := NewScheduleNext(spec)
+	if err != nil {
+		app.WithField(ParamDepth, 1).Error(err)
+		return err
+	}
+	app.Go(func(ctx context.Context) {
+		for {
+			now := time.Now()
+			t := next(now)
+			if t.IsZero() {
+				return
+			}
+			timer := time.NewTimer(t.Sub(now))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				app.runSchedule(ctx, spec, fn)
+			}
+		}
+	})
+	return nil
+}
+
+func (app *App) runSchedule(ctx context.Context, spec string,
+	fn func(ctx context.Context),
+) {
+	now := time.Now()
+	app.WithField("schedule", spec).Debug("eudore app schedule start")
+	defer func() {
+		log := app.WithFields(
+			[]string{"schedule", "duration"},
+			[]any{spec, time.Since(now).String()},
+		)
+		r := recover()
+		if r == nil {
+			log.Info("eudore app schedule done")
+			return
+		}
+
+		recoverPanic(log, r, false)
+	}()
+	fn(ctx)
+}
+
+func (app *App) waitTasks() {
+	done := make(chan struct{})
+	go func() {
+		app.tasks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(DefaultServerShutdownWait):
+		app.Warning(ErrAppWaitTasksTimeout)
+	}
+}
+
+// The Recover method must be called as defer app.Recover(),
+// recovers the panic outside of request handling and outputs
+// the [LoggerFatal] log with the stack.
+// If [Logger] uses [NewLoggerHookFatal], the App will be shutdown gracefully.
+//
+// The goroutines of [App.Serve] and [App.Parse] are covered,
+// [App.Go] is covered when fatal is true,
+// other goroutines need to call it by themselves.
+func (app *App) Recover() {
+	r := recover()
+	if r != nil {
+		recoverPanic(app, r, true)
+	}
+}
+
+// The recoverPanic function converts the recovered value to error,
+// outputs the [LoggerError] or [LoggerFatal] log with the stack.
+//
+// It must be called directly by the deferred function to skip stacks.
+func recoverPanic(log Logger, r any, fatal bool) error {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	log = log.WithField("stack", GetCallerStacks(4))
+	if fatal {
+		log.Fatal(err)
+	} else {
+		log.Error(err)
+	}
+	return err
+}
+
 // The Parse method calls [Config].Parse and handles errors.
 // Use the current [App] as [context.Context].
 //
 // The Parse method executes all [ConfigParseFunc].
 // If the parsing function returns error, it stops parsing and returns error.
-func (app *App) Parse() error {
-	err := app.Config.Parse(app)
+// If the parsing function panics, it is recovered as error.
+func (app *App) Parse() (err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			app.SetValue(ContextKeyError, recoverPanic(app, r, true))
+			err = app.Err()
+		}
+	}()
+	err = app.Config.Parse(app)
 	if err != nil {
 		app.SetValue(ContextKeyError, err)
 	}
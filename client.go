@@ -3,6 +3,7 @@ package eudore
 import (
 	"bytes"
 	"context"
+	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -339,6 +340,28 @@ func NewClientBodyProtobuf(data any) ClientBody {
 	)
 }
 
+// The NewClientBodyGob function creates [ClientBody] with [gob] encoder.
+func NewClientBodyGob(data any) ClientBody {
+	return NewClientBodyDecoder(MimeApplicationGob, data,
+		func(w io.Writer, data any) {
+			_ = gob.NewEncoder(w).Encode(data)
+		},
+	)
+}
+
+// The NewClientBodyMsgpack function creates [ClientBody] with
+// [DefaultMsgpackMarshal] encoder.
+func NewClientBodyMsgpack(data any) ClientBody {
+	return NewClientBodyDecoder(MimeApplicationMsgpack, data,
+		func(w io.Writer, data any) {
+			if DefaultMsgpackMarshal != nil {
+				body, _ := DefaultMsgpackMarshal(data)
+				_, _ = w.Write(body)
+			}
+		},
+	)
+}
+
 // The NewClientBodyDecoder function creates [ClientBody] encoder,
 // which needs to specify [HeaderContentType] and encoder.
 func NewClientBodyDecoder(contenttype string, data any,
@@ -574,8 +597,8 @@ func NewClientCheckStatus(status ...int) func(*http.Response) error {
 // data type is *string or [io.Writer] to write data directly.
 //
 // If the [HeaderContentType] value is [MimeApplicationJSON]
-// [MimeApplicationProtobuf] [MimeApplicationXML],
-// use the corresponding Decoder to parse.
+// [MimeApplicationProtobuf] [MimeApplicationXML] [MimeApplicationGob]
+// [MimeApplicationMsgpack], use the corresponding Decoder to parse.
 func NewClientParse(data any) func(*http.Response) error {
 	return func(w *http.Response) error {
 		return clientParseIn(w, 0, 0xffffffff, data)
@@ -664,6 +687,17 @@ func clientParseIn(w *http.Response, star, end int, data any) error {
 		return NewProtobufDecoder(w.Body).Decode(data)
 	case MimeApplicationXML:
 		return xml.NewDecoder(w.Body).Decode(data)
+	case MimeApplicationGob:
+		return gob.NewDecoder(w.Body).Decode(data)
+	case MimeApplicationMsgpack:
+		if DefaultMsgpackUnmarshal == nil {
+			return ErrHandlerDataMsgpackNotSet
+		}
+		body, err := io.ReadAll(w.Body)
+		if err != nil {
+			return err
+		}
+		return DefaultMsgpackUnmarshal(body, data)
 	}
 	return fmt.Errorf(ErrClientParseBodyError, mime)
 }
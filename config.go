@@ -1,9 +1,11 @@
 package eudore
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -11,8 +13,10 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"text/tabwriter"
 	"time"
 )
 
@@ -170,7 +174,8 @@ func (c *configStd) Parse(ctx context.Context) error {
 	for _, fn := range c.Funcs {
 		c.Err = fn(ctx, c)
 		if c.Err != nil {
-			if !errors.Is(c.Err, context.Canceled) {
+			if !errors.Is(c.Err, context.Canceled) &&
+				!errors.Is(c.Err, flag.ErrHelp) {
 				// replace logger in parsing
 				name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
 				c.Err = fmt.Errorf(ErrConfigParseError, name, c.Err)
@@ -197,6 +202,104 @@ func (c *configStd) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, &c.Data)
 }
 
+// The NewHandlerConfig function creates [HandlerFunc] to render the
+// effective config data as json.
+//
+// The struct field key uses the alias tag, the field with the tag `alias:"-"`
+// is skipped, the value of the field with the tag `secret:"true"` is replaced
+// with [DefaultConfigSecretValue].
+//
+// If config is created by [NewConfig], the data is read with the read lock.
+func NewHandlerConfig(config Config) HandlerFunc {
+	return func(ctx Context) {
+		var data any
+		c, ok := config.(*configStd)
+		if ok {
+			c.Lock.RLock()
+			data = getConfigRedact(reflect.ValueOf(c.Data),
+				make(map[uintptr]struct{}),
+			)
+			c.Lock.RUnlock()
+		} else {
+			data = getConfigRedact(reflect.ValueOf(config.Get("")),
+				make(map[uintptr]struct{}),
+			)
+		}
+		_ = HandlerDataRenderJSON(ctx, data)
+	}
+}
+
+func getConfigRedact(v reflect.Value, refs map[uintptr]struct{}) any {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		_, ok := refs[v.Pointer()]
+		if ok {
+			return nil
+		}
+		refs[v.Pointer()] = struct{}{}
+		defer delete(refs, v.Pointer())
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return getConfigRedact(v.Elem(), refs)
+	case reflect.Invalid, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		return getConfigRedact(v.Elem(), refs)
+	case reflect.Struct:
+		if v.CanAddr() && isJSONMarshaler(v.Type()) {
+			return v.Addr().Interface()
+		}
+		if v.Type().Implements(typeJSONMarshaler) ||
+			v.Type().Implements(typeTextMarshaler) {
+			return v.Interface()
+		}
+		data := make(map[string]any)
+		iType := v.Type()
+		for i := 0; i < iType.NumField(); i++ {
+			field := iType.Field(i)
+			name := field.Tag.Get("alias")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if GetAnyByString[bool](field.Tag.Get("secret")) {
+				data[name] = DefaultConfigSecretValue
+			} else {
+				data[name] = getConfigRedact(v.Field(i), refs)
+			}
+		}
+		return data
+	case reflect.Map:
+		data := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			data[fmt.Sprint(iter.Key().Interface())] = getConfigRedact(iter.Value(), refs)
+		}
+		return data
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		data := make([]any, v.Len())
+		for i := range data {
+			data[i] = getConfigRedact(v.Index(i), refs)
+		}
+		return data
+	default:
+		return v.Interface()
+	}
+}
+
 // The NewConfigParseJSON function creates [ConfigParseFunc] to parse the json
 // configuration file.
 //
@@ -323,39 +426,270 @@ func NewConfigParseEnvs(prefix string) ConfigParseFunc {
 	}
 }
 
+// The NewConfigParseExpand function creates [ConfigParseFunc] to expand
+// the '${NAME}' and '$NAME' placeholders in the string values of [Config],
+// '$$' is expanded to '$'.
+//
+// The lookup func defaults to [os.LookupEnv] and can read non-env sources.
+//
+// If strict is false, the undefined placeholder is left intact;
+// otherwise return error.
+//
+// Nested struct fields, map values and slice elements are expanded,
+// the struct that is not addressable is ignored.
+func NewConfigParseExpand(lookup func(string) (string, bool), strict bool,
+) ConfigParseFunc {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return func(_ context.Context, conf Config) error {
+		expand := &configExpand{Lookup: lookup, Strict: strict}
+		return expand.Value(reflect.ValueOf(conf.Get("")))
+	}
+}
+
+type configExpand struct {
+	Lookup func(string) (string, bool)
+	Strict bool
+}
+
+func (e *configExpand) Value(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return e.Value(v.Elem())
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Elem().Kind() == reflect.String && v.CanSet() {
+			str, err := e.String(v.Elem().String())
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(str))
+			return nil
+		}
+		return e.Value(v.Elem())
+	case reflect.String:
+		if v.CanSet() {
+			str, err := e.String(v.String())
+			if err != nil {
+				return err
+			}
+			v.SetString(str)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				err := e.Value(v.Field(i))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := e.Value(v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			val := reflect.New(iter.Value().Type()).Elem()
+			val.Set(iter.Value())
+			err := e.Value(val)
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), val)
+		}
+	}
+	return nil
+}
+
+// The String method expands the placeholders in str.
+func (e *configExpand) String(str string) (string, error) {
+	if !strings.Contains(str, "$") {
+		return str, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(str); i++ {
+		if str[i] != '$' || i+1 == len(str) {
+			b.WriteByte(str[i])
+			continue
+		}
+
+		var name string
+		end := i + 1
+		switch {
+		case str[end] == '$':
+			b.WriteByte('$')
+			i = end
+			continue
+		case str[end] == '{':
+			pos := strings.IndexByte(str[end:], '}')
+			if pos == -1 {
+				b.WriteByte(str[i])
+				continue
+			}
+			name, end = str[end+1:end+pos], end+pos+1
+		default:
+			for end < len(str) && isConfigExpandChar(str[end]) {
+				end++
+			}
+			name = str[i+1 : end]
+		}
+
+		val, ok := e.Lookup(name)
+		switch {
+		case ok:
+			b.WriteString(val)
+		case e.Strict && name != "":
+			return "", fmt.Errorf(ErrConfigParseExpandUndefined, name)
+		default:
+			b.WriteString(str[i:end])
+		}
+		i = end - 1
+	}
+	return b.String(), nil
+}
+
+func isConfigExpandChar(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9'
+}
+
 // The NewConfigParseArgs function creates [ConfigParseFunc] to parse [os.Args]
 // into [Config].
 //
 // Command line parameters use '--{key}.{sub}={value}' format,
-// short parameters use '-{key}={value}'.
+// short parameters use '-{key}={value}',
+// the flag without '=' does not consume the next argument.
 //
-// if the struct has 'flag' tag, will be used as an abbreviation for the path.
+// refer: [NewConfigParseFlags].
 func NewConfigParseArgs() ConfigParseFunc {
 	return func(ctx context.Context, conf Config) error {
-		log := NewLoggerWithContext(ctx)
-		args := []string{}
-		// Initialize shorts using struct flag tag
-		shorts := newStructShorts(conf.Get(""))
-		for _, str := range os.Args[1:] {
-			key, val, _ := strings.Cut(str, "=")
-			switch {
-			case strings.HasPrefix(key, "--"): // full param
-				log.Info("set os argument: " + str)
-				_ = conf.Set(key[2:], val)
-			case len(key) > 1 && key[0] == '-' && key[1] != '-': // short param
-				for _, lkey := range shorts[key[1:]] {
-					log.Infof("set os short argument: %s --%s=%s",
-						key[1:], lkey, val,
-					)
-					_ = conf.Set(lkey, val)
-				}
-			default:
-				args = append(args, str)
-			}
+		return parseConfigFlags(ctx, conf, os.Args[1:], false)
+	}
+}
+
+// The NewConfigParseFlags function creates [ConfigParseFunc] to parse
+// command line flags into [Config], if args is nil use [os.Args][1:].
+//
+// Command line parameters use '--{key}.{sub}={value}' or
+// '--{key}.{sub} {value}' format, short parameters use '-{key}={value}' or
+// '-{key} {value}'.
+// The bool values do not consume the next argument,
+// the next argument starting with '-' is consumed only if it is a number,
+// such as '--offset -5'.
+//
+// Repeated flags will be appended when the target is a slice.
+//
+// if the struct has 'flag' tag, will be used as an abbreviation for the path.
+//
+// The '--help' flag will output all keys of the struct to [os.Stdout] and
+// return [flag.ErrHelp] to stop parsing,
+// and the parameters after '--' are not parsed as flags.
+func NewConfigParseFlags(args []string) ConfigParseFunc {
+	return func(ctx context.Context, conf Config) error {
+		flags := args
+		if flags == nil {
+			flags = os.Args[1:]
 		}
-		_ = conf.Set("args", args)
-		return nil
+		return parseConfigFlags(ctx, conf, flags, true)
+	}
+}
+
+// The parseConfigFlags function parses flags into [Config],
+// if consume is true, the flag without '=' uses the next argument as value.
+func parseConfigFlags(ctx context.Context, conf Config, flags []string,
+	consume bool,
+) error {
+	log := NewLoggerWithContext(ctx)
+	data := conf.Get("")
+	// Initialize shorts using struct flag tag
+	each := newStructTags(data)
+	positional := []string{}
+	for i := 0; i < len(flags); i++ {
+		str := flags[i]
+		if str == "--" {
+			positional = append(positional, flags[i+1:]...)
+			break
+		}
+		if str == "--help" {
+			printConfigUsage(os.Stdout, data)
+			return flag.ErrHelp
+		}
+
+		var keys []string
+		key, val, ok := strings.Cut(str, "=")
+		switch {
+		case strings.HasPrefix(key, "--"): // full param
+			keys = []string{key[2:]}
+		case len(key) > 1 && key[0] == '-' && key[1] != '-': // short param
+			keys = each.shorts[key[1:]]
+		default:
+			positional = append(positional, str)
+			continue
+		}
+
+		if consume && !ok && i+1 < len(flags) && isConfigFlagValue(flags[i+1]) &&
+			len(keys) > 0 && !isConfigBool(conf.Get(keys[0])) {
+			i++
+			val = flags[i]
+		}
+		for _, k := range keys {
+			log.Infof("set os argument: %s --%s=%s", key, k, val)
+			_ = conf.Set(k, val)
+		}
+	}
+	_ = conf.Set("args", positional)
+	return nil
+}
+
+// The isConfigFlagValue function checks whether the argument can be used as
+// the flag value, the negative number is not a short flag.
+func isConfigFlagValue(str string) bool {
+	if !strings.HasPrefix(str, "-") {
+		return true
 	}
+	_, err := strconv.ParseFloat(str, 64)
+	return err == nil
+}
+
+func isConfigBool(val any) bool {
+	switch val.(type) {
+	case bool, *bool:
+		return true
+	}
+	return false
+}
+
+// The GetConfigUsage function walks the config struct and returns the usage
+// table, including the key path, type, default value and description.
+//
+// Key uses the 'alias' tag as the name, description uses the 'description'
+// tag, and the default value is the current non-zero value of the field.
+func GetConfigUsage(data any) string {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTYPE\tDEFAULT\tDESCRIPTION")
+	for _, usage := range newStructTags(data).usages {
+		fmt.Fprintf(w, "--%s\t%s\t%s\t%s\n",
+			usage.Key, usage.Type, usage.Default, usage.Description,
+		)
+	}
+	w.Flush()
+	return buf.String()
+}
+
+func printConfigUsage(w io.Writer, data any) {
+	fmt.Fprintf(w, "Usage of %s:\n%s", filepath.Base(os.Args[0]), GetConfigUsage(data))
 }
 
 // The NewConfigParseWorkdir function creates [ConfigParseFunc] to initializes
@@ -469,6 +803,7 @@ env to keys:
 	ENV_LOGGER_ENTRY_FIELDS_LENGTH        => DefaultLoggerEntryFieldsLength
 	ENV_LOGGER_FORMATTER                  => DefaultLoggerFormatter
 	ENV_LOGGER_FORMATTER_FORMAT_TIME      => DefaultLoggerFormatterFormatTime
+	ENV_LOGGER_FORMATTER_FLOAT_PRECISION  => DefaultLoggerFormatterFloatPrecision
 	ENV_LOGGER_FORMATTER_KEY_LEVEL        => DefaultLoggerFormatterKeyLevel
 	ENV_LOGGER_FORMATTER_KEY_MESSAGE      => DefaultLoggerFormatterKeyMessage
 	ENV_LOGGER_FORMATTER_KEY_TIME         => DefaultLoggerFormatterKeyTime
@@ -497,6 +832,7 @@ func NewConfigParseDefault() ConfigParseFunc {
 		parseEnvDefault(&DefaultLoggerEntryFieldsLength, "LOGGER_ENTRY_FIELDS_LENGTH")
 		parseEnvDefault(&DefaultLoggerFormatter, "LOGGER_FORMATTER")
 		parseEnvDefault(&DefaultLoggerFormatterFormatTime, "LOGGER_FORMATTER_FORMAT_TIME")
+		parseEnvDefault(&DefaultLoggerFormatterFloatPrecision, "LOGGER_FORMATTER_FLOAT_PRECISION")
 		parseEnvDefault(&DefaultLoggerFormatterKeyLevel, "LOGGER_FORMATTER_KEY_LEVEL")
 		parseEnvDefault(&DefaultLoggerFormatterKeyMessage, "LOGGER_FORMATTER_KEY_MESSAGE")
 		parseEnvDefault(&DefaultLoggerFormatterKeyTime, "LOGGER_FORMATTER_KEY_TIME")
@@ -522,16 +858,24 @@ func parseEnvDefault[T string | bool | time.Time | time.Duration |
 
 type eachTags struct {
 	shorts map[string][]string
+	usages []configUsage
 	repeat map[uintptr]string
 }
 
-func newStructShorts(data any) map[string][]string {
+type configUsage struct {
+	Key         string
+	Type        string
+	Default     string
+	Description string
+}
+
+func newStructTags(data any) *eachTags {
 	each := &eachTags{
 		shorts: make(map[string][]string),
 		repeat: make(map[uintptr]string),
 	}
 	each.Each("", reflect.ValueOf(data))
-	return each.shorts
+	return each
 }
 
 func (each *eachTags) Each(prefix string, v reflect.Value) {
@@ -556,23 +900,43 @@ func (each *eachTags) Each(prefix string, v reflect.Value) {
 		iType := v.Type()
 		for i := 0; i < iType.NumField(); i++ {
 			if v.Field(i).CanSet() {
-				flag := iType.Field(i).Tag.Get("flag")
-				name := iType.Field(i).Tag.Get("alias")
+				field := iType.Field(i)
+				flag := field.Tag.Get("flag")
+				name := field.Tag.Get("alias")
 				if name == "" {
-					name = iType.Field(i).Name
+					name = field.Name
 				}
 
-				if flag != "" && getEachValueKind(iType.Field(i).Type) {
-					val := strings.TrimPrefix(prefix+"."+name, ".")
-					each.shorts[flag] = append(each.shorts[flag], val)
-				} else {
-					each.Each(prefix+"."+name, v.Field(i))
+				path := strings.TrimPrefix(prefix+"."+name, ".")
+				switch {
+				case getEachValueKind(field.Type):
+					each.usages = append(each.usages, newConfigUsage(path, field, v.Field(i)))
+					if flag != "" {
+						each.shorts[flag] = append(each.shorts[flag], path)
+					}
+				case field.Type.Kind() == reflect.Slice &&
+					getEachValueKind(field.Type.Elem()):
+					each.usages = append(each.usages, newConfigUsage(path, field, v.Field(i)))
+				default:
+					each.Each(path, v.Field(i))
 				}
 			}
 		}
 	}
 }
 
+func newConfigUsage(path string, field reflect.StructField, v reflect.Value) configUsage {
+	usage := configUsage{
+		Key:         path,
+		Type:        field.Type.String(),
+		Description: field.Tag.Get("description"),
+	}
+	if !v.IsZero() {
+		usage.Default = fmt.Sprint(reflect.Indirect(v).Interface())
+	}
+	return usage
+}
+
 func getEachValueKind(iType reflect.Type) bool {
 	switch iType.Kind() {
 	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
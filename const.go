@@ -3,9 +3,12 @@ package eudore
 // const defines all global variables and constants
 
 import (
+	"context"
 	"encoding"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"time"
 )
@@ -21,6 +24,9 @@ var (
 	typeFmtStringer   = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
 	typeJSONMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
 	typeTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeTextUnmarshal = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	typeNetIPNet      = reflect.TypeOf((*net.IPNet)(nil)).Elem()
+	typeURL           = reflect.TypeOf((*url.URL)(nil)).Elem()
 	// check interface.
 	_ Client          = (*clientStd)(nil)
 	_ ClientHook      = (*clientHookCookie)(nil)
@@ -34,6 +40,7 @@ var (
 	_ ClientBody      = (*bodyForm)(nil)
 	_ Config          = (*configStd)(nil)
 	_ Context         = (*contextBase)(nil)
+	_ context.Context = (Context)(nil)
 	_ Controller      = (*ControllerAutoRoute)(nil)
 	_ Controller      = (*ControllerAutoType[any])(nil)
 	_ Controller      = (*controllerError)(nil)
@@ -188,6 +195,7 @@ const (
 	HeaderContentType                     = "Content-Type"
 	HeaderCookie                          = "Cookie"
 	HeaderDate                            = "Date"
+	HeaderDeprecation                     = "Deprecation"
 	HeaderETag                            = "Etag"
 	HeaderEarlyData                       = "Early-Data"
 	HeaderExpect                          = "Expect"
@@ -197,6 +205,7 @@ const (
 	HeaderForwarded                       = "Forwarded"
 	HeaderFrom                            = "From"
 	HeaderHost                            = "Host"
+	HeaderIdempotencyKey                  = "Idempotency-Key"
 	HeaderIfMatch                         = "If-Match"
 	HeaderIfModifiedSince                 = "If-Modified-Since"
 	HeaderIfNoneMatch                     = "If-None-Match"
@@ -207,6 +216,7 @@ const (
 	HeaderLastEventID                     = "Last-Event-Id"
 	HeaderLastRetry                       = "Last-Retry"
 	HeaderLastModified                    = "Last-Modified"
+	HeaderLink                            = "Link"
 	HeaderLocation                        = "Location"
 	HeaderOrigin                          = "Origin"
 	HeaderPragma                          = "Pragma"
@@ -225,6 +235,7 @@ const (
 	HeaderSetCookie                       = "Set-Cookie"
 	HeaderSourceMap                       = "SourceMap"
 	HeaderStrictTransportSecurity         = "Strict-Transport-Security"
+	HeaderSunset                          = "Sunset"
 	HeaderTE                              = "Te"
 	HeaderTimingAllowOrigin               = "Timing-Allow-Origin"
 	HeaderTk                              = "Tk"
@@ -251,6 +262,7 @@ const (
 	HeaderXRealIP                         = "X-Real-Ip"
 	HeaderXRequestID                      = "X-Request-Id"
 	HeaderXTraceID                        = "X-Trace-Id"
+	HeaderXEudoreHandlers                 = "X-Eudore-Handlers"
 	HeaderXEudoreRoute                    = "X-Eudore-Route"
 
 	HeaderValueChunked   = "chunked"
@@ -268,12 +280,17 @@ const (
 	MimeTextJavascript             = "text/javascript"
 	MimeTextHTML                   = "text/html"
 	MimeTextCSS                    = "text/css"
+	MimeTextCSV                    = "text/csv"
 	MimeTextXML                    = "text/xml"
 	MimeTextEventStream            = "text/event-stream"
 	MimeApplicationYAML            = "application/yaml"
+	MimeApplicationJavascript      = "application/javascript"
 	MimeApplicationXML             = "application/xml"
 	MimeApplicationProtobuf        = "application/protobuf"
+	MimeApplicationGob             = "application/x-gob"
+	MimeApplicationMsgpack         = "application/msgpack"
 	MimeApplicationJSON            = "application/json"
+	MimeApplicationNDJSON          = "application/x-ndjson"
 	MimeApplicationForm            = "application/x-www-form-urlencoded"
 	MimeApplicationOctetStream     = "application/octet-stream"
 	MimeMultipartForm              = "multipart/form-data"
@@ -284,6 +301,7 @@ const (
 	MimeTextJavascriptCharsetUtf8  = MimeTextJavascript + "; " + MimeCharsetUtf8
 	MimeTextHTMLCharsetUtf8        = MimeTextHTML + "; " + MimeCharsetUtf8
 	MimeTextCSSCharsetUtf8         = MimeTextCSS + "; " + MimeCharsetUtf8
+	MimeTextCSVCharsetUtf8         = MimeTextCSV + "; " + MimeCharsetUtf8
 	MimeTextXMLCharsetUtf8         = MimeTextXML + "; " + MimeCharsetUtf8
 	MimeApplicationYAMLCharsetUtf8 = MimeApplicationYAML + "; " + MimeCharsetUtf8
 	MimeApplicationXMLCharsetUtf8  = MimeApplicationXML + "; " + MimeCharsetUtf8
@@ -293,16 +311,22 @@ const (
 
 	ParamAction          = "action"
 	ParamAllow           = "allow"
+	ParamAttachment      = "attachment"
 	ParamAutoIndex       = "autoindex"
 	ParamBasicAuth       = "basicauth"
+	ParamCache           = "cache"
 	ParamCaller          = "caller"
 	ParamControllerGroup = "controllergroup"
 	ParamDepth           = "depth"
 	ParamLoggerKind      = "loggerkind"
 	ParamPrefix          = "prefix"
+	ParamPriority        = "priority"
+	ParamRateLimit       = "ratelimit"
 	ParamTemplate        = "template"
 	ParamRoute           = "route"
 	ParamRouteHost       = "route-host"
+	ParamRouteSuggest    = "route-suggest"
+	ParamWildcard        = "path"
 	ParamUserid          = "Userid"
 	ParamUsername        = "Username"
 	ParamPolicy          = "Policy"
@@ -6,15 +6,19 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Context defines the request context interface,
@@ -31,6 +35,14 @@ type Context interface {
 	// Context Get the context of the current request.
 	// ctx.Request().Context() is not equal to ctx.Context().
 	Context() context.Context
+	// The Deadline Done Err methods delegate to the current context,
+	// [Context] implements the [context.Context] interface and can be
+	// passed to context-aware functions.
+	//
+	// The Context is pooled and must not be used after the request ends,
+	// use ctx.Context() for goroutines.
+	Deadline() (time.Time, bool)
+	Done() <-chan struct{}
 	Request() *http.Request
 	Response() ResponseWriter
 	Value(key any) any
@@ -38,9 +50,18 @@ type Context interface {
 	SetRequest(r *http.Request)
 	SetResponse(w ResponseWriter)
 	// SetValue sets the Value of the built-in [context.Context],
-	// which can be read by calling the [Value] method.
+	// which can be read by calling the [Value] method or [GetContextValue].
+	//
+	// The value is only valid for the current request and is cleared when
+	// the pooled Context is Reset.
 	//
 	// String type parameters are prioritized using [SetParam].
+	//
+	// If the key is [ContextKeyLogger], the [Logger] is saved with the field
+	// logger=true, so the fields added by ctx.WithField are inherited by
+	// the later log methods of the request:
+	//
+	//	ctx.SetValue(eudore.ContextKeyLogger, ctx.WithField("userid", id))
 	SetValue(key any, val any)
 	// handles
 	SetHandlers(index int, handlers []HandlerFunc)
@@ -53,6 +74,13 @@ type Context interface {
 	Next()
 	// End Ends current handlers of the request context.
 	//
+	// The index is set to [DefaultContextMaxHandler], Next does not call
+	// any remaining handler, including the handlers appended by combine and
+	// the outer handlers after the nested Next returns.
+	//
+	// After the response has been written, the Write and WriteString
+	// methods discard the data written after End and output a Debug log.
+	//
 	// The Fatal/Fatalf method contains the End method.
 	End()
 	Err() error
@@ -83,10 +111,38 @@ type Context interface {
 	//
 	// If [NewBodyLimitFunc] is used, error may return [http.MaxBytesError].
 	Body() ([]byte, error)
+	// The AsCurl method serializes the request into a curl command for
+	// debugging, the body is read using the Body method and previews up to
+	// [DefaultContextCurlBodySize] bytes.
+	//
+	// The value of [DefaultContextCurlRedactHeaders] is replaced with
+	// [DefaultContextCurlRedactValue].
+	AsCurl() string
 	// Bind uses the [ContextKeyBind] function loaded
 	// in [NewContextBaseFunc] to bind data.
 	// Use [NewHandlerDataBinds] by default.
+	//
+	// If the error does not implement the Status method, wrap the status
+	// [DefaultContextBindErrorStatus], and the json error field and offset
+	// are rendered as message.
+	//
+	// After binding successfully, call the func(Context, any) hook set by
+	// ctx.SetValue([ContextKeyBindHook]).
 	Bind(data any) error
+	// The BindForm method merges the url query and the form body to bind
+	// data, the body value takes precedence over the query value of the
+	// same key, repeated values are bound into slices.
+	//
+	// Using tag [DefaultHandlerDataBindFormTags], regardless of the
+	// Bind function, the error is handled the same as Bind.
+	BindForm(data any) error
+	// The BindMap method uses Bind to bind data to map[string]any,
+	// used for schemaless payloads, check keys using [RequireKeys].
+	BindMap() (map[string]any, error)
+	// The BindAndValidate method uses Bind and then uses the
+	// [ContextKeyValidate] function loaded in [NewContextBaseFunc] to
+	// validate data, the error is returned to the handler to handle.
+	BindAndValidate(data any) error
 
 	// param query header cookie form
 
@@ -94,6 +150,9 @@ type Context interface {
 	// [ParamRoute] gets the route.
 	Params() *Params
 	GetParam(key string) string
+	// The SetParam method adds or overwrites a param, the middleware can
+	// inject params for subsequent handlers, params are reset when the
+	// [Context] is recycled.
 	SetParam(key string, val string)
 	// The Query method returns the uri parameter get
 	// by parsing ctx.Request().URL.RawQuery.
@@ -102,11 +161,26 @@ type Context interface {
 	Querys() (url.Values, error)
 	// refer Querys
 	GetQuery(key string) string
+	// The Pagination method parses the uri parameters page size sort,
+	// the invalid value uses defaults, and the size is capped to MaxSize.
+	//
+	// The sort parameter is a comma-separated list of fields,
+	// the '-' prefix or ':desc' suffix indicates descending order.
+	Pagination(defaults Pagination) Pagination
 	// GetHeader gets a request header, alias ctx.Request().Header().Get(name).
 	GetHeader(key string) string
 	// SetHeader sets a response header,
 	// alias ctx.Response().Header().Set(name, val).
 	SetHeader(key string, val string)
+	// SetETag sets [HeaderETag], the etag is quoted if it is not quoted
+	// or weak.
+	SetETag(etag string)
+	// SetTrailer declares [HeaderTrailer] and sets a response trailer,
+	// which is sent after the body.
+	//
+	// If the response does not support trailers, it is ignored and
+	// output a Debug log.
+	SetTrailer(key string, val string)
 	// Cookies gets all cookies from [HeaderCookie] and
 	// parses the data after the first call to the [Cookies]/[GetCookie] method.
 	Cookies() []Cookie
@@ -123,6 +197,12 @@ type Context interface {
 	//
 	// The parsed data is saved in Request().PostForm or MultipartForm.
 	FormValue(key string) string
+	// refer FormValue, returns val if the value is empty.
+	FormValueDefault(key, val string) string
+	// refer FormValue, converts the value to int using [GetAnyByString].
+	FormValueInt(key string, defaults ...int) int
+	// refer FormValue, returns all values of the repeated key.
+	FormValueList(key string) []string
 	// refer FormValue
 	FormValues() (map[string][]string, error)
 	// refer FormValue
@@ -132,19 +212,54 @@ type Context interface {
 
 	// response
 
+	// The Write and WriteString methods sniff [HeaderContentType] from the
+	// first written data if it is not set.
 	Write(b []byte) (int, error)
 	WriteString(s string) (int, error)
 	// WriteStatus sets the status code but does not write.
 	//
-	// Automatically write code at the first Write or WriteString.
+	// Automatically write code at the first Write or WriteString,
+	// or after all handlers are completed if nothing is written.
 	WriteStatus(code int)
 	// WriteHeader method writing status code and [http.Header],
 	// [http.Header] cannot be set after calling.
 	WriteHeader(code int)
-	// WriteFile opens the file and responds using [http.ServeContent].
+	// The Status method returns the status code set by WriteStatus or
+	// written by WriteHeader, the default is [StatusOK].
+	//
+	// It is also valid for responses without body, such as 204 and 304.
+	Status() int
+	// WriteFile opens the file and responds using [http.ServeContent],
+	// [HeaderContentType] is set from the file extension or sniffed.
 	WriteFile(path string) error
+	// The Attachment method sets [HeaderContentDisposition] to attachment,
+	// the non-ASCII filename is encoded using RFC 5987 filename* param.
+	//
+	// Called before Write/WriteFile.
+	Attachment(filename string)
+	// The Multipart method creates [multipart.Writer] to write the
+	// [MimeMultipartMixed] response, and sets [HeaderContentType] with
+	// the boundary.
+	//
+	// Close must be called to write the final boundary.
+	Multipart() *multipart.Writer
+	// The StreamJSON method creates [json.Encoder] to write the
+	// [MimeApplicationNDJSON] response, each Encode is flushed immediately.
+	//
+	// After the request [context.Context] is done, Encode returns the error.
+	StreamJSON() *json.Encoder
 	// The Redirect method uses [http.Redirect] to redirect url.
+	//
+	// The relative url is resolved against the request path,
+	// and the status code needs to be 30x or 201.
 	Redirect(code int, url string) error
+	// The RedirectSafe method is the same as Redirect,
+	// but the url must be a relative path, or the url host must be the
+	// request host or in hosts, avoid open redirect.
+	//
+	// The url with scheme or starting with '//' and without the allowed
+	// host is rejected, such as 'https:host' and 'http:/host'.
+	RedirectSafe(code int, url string, hosts ...string) error
 	// Render uses the [ContextKeyRender] function loaded
 	// in [NewContextBaseFunc] to Render data.
 	// Use [NewHandlerDataRenders] by default.
@@ -153,6 +268,11 @@ type Context interface {
 	// if the [WriteHeader] method is used,
 	// Render cannot write [HeaderContentType].
 	Render(data any) error
+	// The Abort method writes the status and uses Render to write
+	// the message, then ends request processing.
+	//
+	// If Response.Size!=0, only ends request processing.
+	Abort(status int, message string)
 
 	// Logger interface
 
@@ -163,7 +283,8 @@ type Context interface {
 	// The Fatal method outputs the [LoggerError] logger, returns a message,
 	// and ends request processing.
 	//
-	// Use the [WriteStatus] to write the status code of the render Message.
+	// Use the [WriteStatus] to write the status code of the render Message,
+	// otherwise use the Status method of the error or [RegisterErrorStatus].
 	//
 	// If Response.Size=0, the response will be written.
 	Fatal(args ...any)
@@ -173,6 +294,12 @@ type Context interface {
 	Errorf(format string, args ...any)
 	// refer Fatal
 	Fatalf(format string, args ...any)
+	// The Logger method returns the request-scoped [Logger] used by the
+	// [Context] log methods, including the fields set by middleware
+	// through [ContextKeyLogger].
+	//
+	// The Fatal method of the returned Logger does not end the request.
+	Logger() Logger
 	// The WithField method uses [Logger.WithField] to return the [Logger]
 	// associated with the [Context].
 	// It is not allowed to be used after the HandlerFunc ends.
@@ -205,6 +332,7 @@ type contextBase struct {
 type contextBaseConfig struct {
 	Logger                 Logger
 	Bind                   func(Context, any) error
+	Validate               func(Context, any) error
 	Render                 func(Context, any) error
 	MaxApplicationFormSize int64
 	MaxMultipartFormMemory int64
@@ -276,8 +404,8 @@ func NewContextBasePool(ctx context.Context) *sync.Pool {
 // Load [ContextKeyApp] implement the [Logger] interface from the
 // [context.Context].
 //
-// Load [ContextKeyBind] [ContextKeyRender] is [HandlerDataFunc] from the
-// [context.Context].
+// Load [ContextKeyBind] [ContextKeyValidate] [ContextKeyRender] is
+// [HandlerDataFunc] from the [context.Context].
 //
 // If the [App] updates this data,
 // you need to reset the [ContextKeyContextPool].
@@ -293,16 +421,24 @@ func NewContextBaseFunc(ctx context.Context) func() Context {
 
 func newContextBaseConfig(ctx context.Context) *contextBaseConfig {
 	bind, _ := ctx.Value(ContextKeyBind).(func(Context, any) error)
+	validate, _ := ctx.Value(ContextKeyValidate).(func(Context, any) error)
 	render, _ := ctx.Value(ContextKeyRender).(func(Context, any) error)
 	if bind == nil {
 		bind = NewHandlerDataBinds(nil)
 	}
+	if validate == nil {
+		validate = NewHandlerDataFuncs(
+			NewHandlerDataValidateStruct(ctx),
+			NewHandlerDataValidate(),
+		)
+	}
 	if render == nil {
 		render = NewHandlerDataRenders(nil)
 	}
 	return &contextBaseConfig{
 		Logger:                 NewLoggerWithContext(ctx),
 		Bind:                   bind,
+		Validate:               validate,
 		Render:                 render,
 		MaxApplicationFormSize: DefaultContextMaxApplicationFormSize,
 		MaxMultipartFormMemory: DefaultContextMaxMultipartFormMemory,
@@ -310,15 +446,26 @@ func newContextBaseConfig(ctx context.Context) *contextBaseConfig {
 }
 
 // The Reset function resets the Context data.
+//
+// The pooled Context clears all the data of the previous request,
+// including handlers, params, values, logger, error, cookies and body,
+// and releases the references to avoid leaking into the next request.
 func (ctx *contextBase) Reset(w http.ResponseWriter, r *http.Request) {
+	ctx.index = 0
+	ctx.handlers = nil
 	ctx.context = &ctx.contextValues
 	ctx.ResponseWriter = &ctx.httpResponse
 	ctx.RequestReader = r
-	ctx.params = ctx.params[0:2]
-	ctx.params[1] = ""
+	for i := range ctx.params {
+		ctx.params[i] = ""
+	}
+	ctx.params = append(ctx.params[0:0], ParamRoute, "")
 	ctx.contextValues.Reset(r.Context(), ctx.config)
 	ctx.httpResponse.Reset(w)
 	ctx.wantStatus = StatusOK
+	for i := range ctx.cookies {
+		ctx.cookies[i] = Cookie{}
+	}
 	ctx.cookies = ctx.cookies[:0]
 	ctx.bodyContent = nil
 }
@@ -331,6 +478,14 @@ func (ctx *contextBase) Context() context.Context {
 	return ctx.context
 }
 
+func (ctx *contextBase) Deadline() (time.Time, bool) {
+	return ctx.context.Deadline()
+}
+
+func (ctx *contextBase) Done() <-chan struct{} {
+	return ctx.context.Done()
+}
+
 func (ctx *contextBase) Request() *http.Request {
 	return ctx.RequestReader
 }
@@ -356,6 +511,9 @@ func (ctx *contextBase) SetResponse(w ResponseWriter) {
 }
 
 func (ctx *contextBase) SetValue(key, val any) {
+	if key == ContextKeyLogger {
+		val = getContextLogger(val)
+	}
 	base, ok := ctx.context.(interface{ SetValue(key any, val any) })
 	if ok {
 		base.SetValue(key, val)
@@ -374,8 +532,10 @@ func (ctx *contextBase) GetHandlers() (int, []HandlerFunc) {
 
 func (ctx *contextBase) Next() {
 	ctx.index++
-	for ctx.index < len(ctx.handlers) {
-		ctx.handlers[ctx.index](ctx)
+	for ctx.index < len(ctx.handlers) && ctx.index < DefaultContextMaxHandler {
+		if ctx.handlers[ctx.index] != nil {
+			ctx.handlers[ctx.index](ctx)
+		}
 		ctx.index++
 	}
 }
@@ -461,12 +621,106 @@ func (ctx *contextBase) Body() ([]byte, error) {
 	return ctx.bodyContent, nil
 }
 
+func (ctx *contextBase) AsCurl() string {
+	r := ctx.RequestReader
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "curl -X %s %s", quoteCurlString(r.Method),
+		quoteCurlString(scheme+"://"+r.Host+r.URL.RequestURI()),
+	)
+	keys := make([]string, 0, len(r.Header))
+	for key := range r.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		redact := sliceIndex(DefaultContextCurlRedactHeaders, key) != -1
+		for _, val := range r.Header[key] {
+			if redact {
+				val = DefaultContextCurlRedactValue
+			}
+			fmt.Fprintf(b, " -H %s", quoteCurlString(key+": "+val))
+		}
+	}
+
+	body, _ := ctx.Body()
+	if len(body) > 0 {
+		if len(body) > DefaultContextCurlBodySize {
+			body = body[:DefaultContextCurlBodySize]
+		}
+		fmt.Fprintf(b, " --data-raw %s", quoteCurlString(string(body)))
+	}
+	return b.String()
+}
+
 func (ctx *contextBase) Bind(i any) error {
-	err := ctx.config.Bind(ctx, i)
+	return ctx.bindHook("Context.Bind", i, ctx.config.Bind(ctx, i))
+}
+
+// The bindHook method handles the result of Bind,
+// returns the bind error or calls the [ContextKeyBindHook] function.
+func (ctx *contextBase) bindHook(call string, i any, err error) error {
+	if err != nil {
+		ctx.loggerDebug(call, err)
+		return newBindError(err)
+	}
+	hook, ok := ctx.Value(ContextKeyBindHook).(func(Context, any))
+	if ok {
+		hook(ctx, i)
+	}
+	return nil
+}
+
+func (ctx *contextBase) BindMap() (map[string]any, error) {
+	data := make(map[string]any)
+	err := ctx.Bind(&data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (ctx *contextBase) BindForm(i any) error {
+	querys, err := ctx.Querys()
+	if err != nil {
+		return newBindError(err)
+	}
+	forms, err := ctx.FormValues()
+	if err != nil {
+		return newBindError(err)
+	}
+
+	vals := make(map[string][]string, len(querys)+len(forms))
+	for key, val := range querys {
+		vals[key] = val
+	}
+	for key, val := range forms {
+		vals[key] = val
+	}
+	files := ctx.FormFiles()
+	if len(files) > 0 {
+		err = bindMaps(files, i, DefaultHandlerDataBindFormTags)
+	}
+	if err == nil {
+		err = bindMaps(vals, i, DefaultHandlerDataBindFormTags)
+	}
+	return ctx.bindHook("Context.BindForm", i, err)
+}
+
+func (ctx *contextBase) BindAndValidate(i any) error {
+	err := ctx.Bind(i)
 	if err != nil {
-		ctx.loggerDebug("Context.Bind", err)
 		return err
 	}
+	err = ctx.config.Validate(ctx, i)
+	if err != nil {
+		ctx.loggerDebug("Context.BindAndValidate", err)
+		return newBindError(err)
+	}
 	return nil
 }
 
@@ -508,6 +762,38 @@ func (ctx *contextBase) GetQuery(key string) string {
 	return r.Form.Get(key)
 }
 
+func (ctx *contextBase) Pagination(defaults Pagination) Pagination {
+	if defaults.MaxSize < 1 {
+		defaults.MaxSize = DefaultContextPaginationMaxSize
+	}
+	if defaults.Page < 1 {
+		defaults.Page = 1
+	}
+	if defaults.Size < 1 {
+		defaults.Size = DefaultContextPaginationSize
+	}
+	if defaults.Size > defaults.MaxSize {
+		defaults.Size = defaults.MaxSize
+	}
+
+	page, err := GetAnyByStringWithError[int](ctx.GetQuery("page"))
+	if err == nil && page > 0 {
+		defaults.Page = page
+	}
+	size, err := GetAnyByStringWithError[int](ctx.GetQuery("size"))
+	if err == nil && size > 0 {
+		defaults.Size = size
+		if size > defaults.MaxSize {
+			defaults.Size = defaults.MaxSize
+		}
+	}
+	sorts := getPaginationSorts(ctx.GetQuery("sort"))
+	if sorts != nil {
+		defaults.Sort = sorts
+	}
+	return defaults
+}
+
 func (ctx *contextBase) GetHeader(key string) string {
 	return ctx.RequestReader.Header.Get(key)
 }
@@ -516,6 +802,32 @@ func (ctx *contextBase) SetHeader(key string, val string) {
 	ctx.ResponseWriter.Header().Set(key, val)
 }
 
+func (ctx *contextBase) SetETag(etag string) {
+	if !strings.HasPrefix(etag, `"`) && !strings.HasPrefix(etag, `W/"`) {
+		etag = `"` + etag + `"`
+	}
+	ctx.ResponseWriter.Header().Set(HeaderETag, etag)
+}
+
+// The SetTrailer method sets the trailer using [http.TrailerPrefix],
+// requires HTTP/1.1 chunked or HTTP/2 response.
+func (ctx *contextBase) SetTrailer(key string, val string) {
+	h := ctx.ResponseWriter.Header()
+	if !ctx.RequestReader.ProtoAtLeast(1, 1) ||
+		h.Get(HeaderContentLength) != "" ||
+		ctx.ResponseWriter.Status() == StatusSwitchingProtocols {
+		ctx.logger().WithField(ParamCaller, "Context.SetTrailer").
+			Debugf(ErrContextTrailerNotSupport, key)
+		return
+	}
+
+	key = textproto.CanonicalMIMEHeaderKey(key)
+	if sliceIndex(h.Values(HeaderTrailer), key) == -1 {
+		h.Add(HeaderTrailer, key)
+	}
+	h.Set(http.TrailerPrefix+key, val)
+}
+
 // Cookies gets all cookies from [HeaderCookie] and
 // parses the data after the first call to the [Cookies]/[GetCookie] method.
 func (ctx *contextBase) Cookies() []Cookie {
@@ -569,6 +881,32 @@ func (ctx *contextBase) FormValue(key string) string {
 	return ""
 }
 
+func (ctx *contextBase) FormValueDefault(key, val string) string {
+	if v := ctx.FormValue(key); v != "" {
+		return v
+	}
+	return val
+}
+
+func (ctx *contextBase) FormValueInt(key string, defaults ...int) int {
+	return GetAnyByString(ctx.FormValue(key), defaults...)
+}
+
+// FormValueList uses body to parse Form data and returns all values of the
+// key.
+func (ctx *contextBase) FormValueList(key string) []string {
+	r := ctx.RequestReader
+	if r.PostForm == nil {
+		err := ctx.parseForm(r)
+		if err != nil {
+			r.PostForm = make(url.Values)
+			ctx.loggerDebug("Context.FormValueList", err)
+			return nil
+		}
+	}
+	return r.PostForm[key]
+}
+
 // FormValues uses body to parse Form data and returns all values.
 func (ctx *contextBase) FormValues() (map[string][]string, error) {
 	r := ctx.RequestReader
@@ -625,6 +963,13 @@ func (ctx *contextBase) FormFiles() map[string][]*multipart.FileHeader {
 
 // Write implements [io.Writer] and writes data to the response.
 func (ctx *contextBase) Write(b []byte) (n int, err error) {
+	if ctx.isCommitted() {
+		ctx.discardWrite("Context.Write", len(b))
+		return len(b), nil
+	}
+	if ctx.ResponseWriter.Size() == 0 && len(b) > 0 {
+		ctx.writeContentType(b)
+	}
 	ctx.writeStatus()
 	n, err = ctx.ResponseWriter.Write(b)
 	if err != nil {
@@ -635,6 +980,17 @@ func (ctx *contextBase) Write(b []byte) (n int, err error) {
 
 // WriteString implements [io.StringWriter] and writes a string to the response.
 func (ctx *contextBase) WriteString(s string) (n int, err error) {
+	if ctx.isCommitted() {
+		ctx.discardWrite("Context.WriteString", len(s))
+		return len(s), nil
+	}
+	if ctx.ResponseWriter.Size() == 0 && len(s) > 0 {
+		if len(s) > sniffLen {
+			ctx.writeContentType([]byte(s[:sniffLen]))
+		} else {
+			ctx.writeContentType([]byte(s))
+		}
+	}
 	ctx.writeStatus()
 	n, err = ctx.ResponseWriter.WriteString(s)
 	if err != nil {
@@ -643,6 +999,39 @@ func (ctx *contextBase) WriteString(s string) (n int, err error) {
 	return
 }
 
+// The isCommitted method returns whether End has been called after
+// the response has been written, subsequent writes will be discarded.
+func (ctx *contextBase) isCommitted() bool {
+	return ctx.index >= DefaultContextMaxHandler && ctx.ResponseWriter.Size() > 0
+}
+
+// The discardWrite method outputs the Debug log of discarded data.
+func (ctx *contextBase) discardWrite(call string, size int) {
+	log := ctx.logger()
+	if log.GetLevel() > LoggerDebug {
+		return
+	}
+	log.WithField(ParamDepth, 2).
+		WithField(ParamCaller, call).
+		Debugf(ErrContextWriteAfterEnd, size)
+}
+
+// sniffLen defines the data length used by [http.DetectContentType].
+const sniffLen = 512
+
+// The writeContentType method sniffs [HeaderContentType] from the first
+// written data when it is unset, same as [http.ResponseWriter].
+//
+// The sniffing is done before the response writer wrapped by middleware,
+// compressed data cannot be sniffed by [http.Server].
+func (ctx *contextBase) writeContentType(b []byte) {
+	h := ctx.ResponseWriter.Header()
+	_, ok := h[HeaderContentType]
+	if !ok && h.Get(HeaderContentEncoding) == "" {
+		h.Set(HeaderContentType, http.DetectContentType(b))
+	}
+}
+
 func (ctx *contextBase) writeStatus() {
 	if ctx.wantStatus > 0 {
 		ctx.ResponseWriter.WriteHeader(ctx.wantStatus)
@@ -662,6 +1051,11 @@ func (ctx *contextBase) WriteHeader(code int) {
 	ctx.ResponseWriter.WriteHeader(code)
 }
 
+// The Status method returns the pending or written response status code.
+func (ctx *contextBase) Status() int {
+	return ctx.ResponseWriter.Status()
+}
+
 // WriteFile 使用HandlerFile处理一个静态文件。
 func (ctx *contextBase) WriteFile(path string) error {
 	file, err := os.Open(path)
@@ -677,6 +1071,94 @@ func (ctx *contextBase) WriteFile(path string) error {
 	return nil
 }
 
+// The Attachment method sets the attachment filename of the response.
+func (ctx *contextBase) Attachment(filename string) {
+	ctx.ResponseWriter.Header().Set(HeaderContentDisposition,
+		getContentDisposition("attachment", filename),
+	)
+}
+
+// The Multipart method creates a multipart/mixed response writer.
+func (ctx *contextBase) Multipart() *multipart.Writer {
+	w := multipart.NewWriter(ctx)
+	ctx.ResponseWriter.Header().Set(HeaderContentType,
+		MimeMultipartMixed+"; boundary="+w.Boundary(),
+	)
+	return w
+}
+
+func (ctx *contextBase) StreamJSON() *json.Encoder {
+	h := ctx.ResponseWriter.Header()
+	h.Set(HeaderContentType, MimeApplicationNDJSON)
+	h.Set(HeaderCacheControl, HeaderValueNoCache)
+	return json.NewEncoder(contextStreamWriter{ctx})
+}
+
+// The contextStreamWriter type flushes each write of [Context].StreamJSON.
+type contextStreamWriter struct {
+	ctx *contextBase
+}
+
+func (w contextStreamWriter) Write(b []byte) (int, error) {
+	err := w.ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.ctx.Write(b)
+	if err == nil {
+		w.ctx.ResponseWriter.Flush()
+	}
+	return n, err
+}
+
+// The getContentDisposition function formats the disposition filename,
+// the filename param is an ASCII fallback, and the filename* param
+// is the UTF-8 percent-encoded value defined in RFC 5987.
+func getContentDisposition(kind, filename string) string {
+	if filename == "" {
+		return kind
+	}
+	ascii := true
+	fallback := make([]byte, 0, len(filename))
+	for _, r := range filename {
+		switch {
+		case r >= 0x80 || r < 0x20 || r == 0x7f:
+			ascii = false
+			fallback = append(fallback, '_')
+		case r == '"' || r == '\\':
+			fallback = append(fallback, '\\', byte(r))
+		default:
+			fallback = append(fallback, byte(r))
+		}
+	}
+	if ascii {
+		return fmt.Sprintf(`%s; filename="%s"`, kind, fallback)
+	}
+
+	const hex = "0123456789ABCDEF"
+	encoded := make([]byte, 0, len(filename)*3)
+	for i := 0; i < len(filename); i++ {
+		c := filename[i]
+		if isAttrChar(c) {
+			encoded = append(encoded, c)
+		} else {
+			encoded = append(encoded, '%', hex[c>>4], hex[c&0xf])
+		}
+	}
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`,
+		kind, fallback, encoded,
+	)
+}
+
+// RFC 5987 attr-char.
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) != -1
+}
+
 // Redirect implements request redirection.
 // The status code needs to be 30x or 201.
 func (ctx *contextBase) Redirect(code int, u string) error {
@@ -694,6 +1176,37 @@ func (ctx *contextBase) Redirect(code int, u string) error {
 	return nil
 }
 
+func (ctx *contextBase) RedirectSafe(code int, u string, hosts ...string) error {
+	if !isRedirectSafe(u, ctx.Host(), hosts) {
+		err := fmt.Errorf(ErrContextRedirectUnsafe, u)
+		ctx.internalError("Context.RedirectSafe", err)
+		return err
+	}
+	return ctx.Redirect(code, u)
+}
+
+// The isRedirectSafe function checks whether the url is a relative path or
+// its host is the request host or in hosts.
+//
+// The url is normalized like the browser: trim the leading spaces and
+// control characters, remove tab and newline, and treat '\' as '/'.
+func isRedirectSafe(u, host string, hosts []string) bool {
+	u = strings.TrimLeftFunc(u, func(r rune) bool { return r <= ' ' })
+	u = strings.NewReplacer("\\", "/", "\t", "", "\n", "", "\r", "").Replace(u)
+	uri, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	if uri.Scheme == "" && uri.Opaque == "" && uri.Host == "" &&
+		!strings.HasPrefix(u, "//") {
+		return true
+	}
+	if uri.Opaque != "" || uri.Host == "" {
+		return false
+	}
+	return uri.Host == host || sliceIndex(hosts, uri.Host) != -1
+}
+
 // Render uses Render to return data.
 func (ctx *contextBase) Render(data any) error {
 	err := ctx.config.Render(ctx, data)
@@ -704,6 +1217,14 @@ func (ctx *contextBase) Render(data any) error {
 	return nil
 }
 
+func (ctx *contextBase) Abort(status int, message string) {
+	if ctx.ResponseWriter.Size() == 0 {
+		ctx.WriteStatus(status)
+		_ = ctx.Render(NewContextMessgae(ctx, nil, message))
+	}
+	ctx.End()
+}
+
 // The writeFatal method returns error data.
 //
 // If the response is not written, return error; and end request processing.
@@ -713,12 +1234,11 @@ func (ctx *contextBase) Render(data any) error {
 func (ctx *contextBase) writeFatal(err error) {
 	w := ctx.ResponseWriter
 	if w.Size() == 0 {
-		msg := NewContextMessgae(ctx, err, nil)
 		status := w.Status()
 		if status == StatusOK {
 			ctx.WriteStatus(getErrorStatus(err))
 		}
-		_ = ctx.Render(msg)
+		_ = ctx.Render(NewContextMessgae(ctx, err, getErrorMessage(err)))
 	}
 	base, ok := ctx.context.Value(&baseCtxKey).(*contextBaseValue)
 	if ok {
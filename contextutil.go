@@ -3,6 +3,7 @@ package eudore
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -32,6 +33,9 @@ func (ctx *contextBaseValue) Reset(c context.Context, conf *contextBaseConfig) {
 	ctx.Context = c
 	ctx.Logger = conf.Logger
 	ctx.Error = nil
+	for i := range ctx.Values {
+		ctx.Values[i] = nil
+	}
 	ctx.Values = ctx.Values[0:0]
 }
 
@@ -142,6 +146,20 @@ func (e *contextBaseEntry) WithFields(keys []string, fields []any) Logger {
 	return e
 }
 
+// The getContextLogger function unwraps [contextBaseEntry] and sets the
+// field logger=true, the one-time log entry returned by WithField becomes
+// a reusable [Logger], otherwise each output reuses the entry and the
+// message and fields accumulate.
+func getContextLogger(val any) any {
+	if e, ok := val.(*contextBaseEntry); ok {
+		val = e.Logger
+	}
+	if log, ok := val.(Logger); ok {
+		return log.WithField("logger", true)
+	}
+	return val
+}
+
 // responseWriterHTTP is a wrapper for the [http.ResponseWriter] interface.
 type responseWriterHTTP struct {
 	http.ResponseWriter
@@ -230,6 +248,74 @@ func (w *responseWriterHTTP) Status() int {
 	return (w.code + m) ^ m
 }
 
+// Pagination defines the page params parsed by [Context].Pagination.
+type Pagination struct {
+	Page    int              `alias:"page" json:"page" yaml:"page"`
+	Size    int              `alias:"size" json:"size" yaml:"size"`
+	MaxSize int              `alias:"maxSize" json:"-" yaml:"-"`
+	Sort    []PaginationSort `alias:"sort" json:"sort,omitempty" yaml:"sort,omitempty"`
+}
+
+// PaginationSort defines a sort field and direction of [Pagination].
+type PaginationSort struct {
+	Field string `alias:"field" json:"field" yaml:"field"`
+	Desc  bool   `alias:"desc" json:"desc" yaml:"desc"`
+}
+
+// The getPaginationSorts function parses sort directives like
+// "-created,name:asc", invalid field names are ignored.
+func getPaginationSorts(str string) []PaginationSort {
+	var sorts []PaginationSort
+	for _, field := range strings.Split(str, ",") {
+		var sort PaginationSort
+		field = strings.TrimSpace(field)
+		switch {
+		case strings.HasPrefix(field, "-"):
+			field, sort.Desc = field[1:], true
+		case strings.HasPrefix(field, "+"):
+			field = field[1:]
+		default:
+			name, order, ok := strings.Cut(field, ":")
+			if ok {
+				switch strings.ToLower(order) {
+				case "desc":
+					sort.Desc = true
+				case "asc":
+				default:
+					continue
+				}
+				field = name
+			}
+		}
+		if isPaginationField(field) {
+			sort.Field = field
+			sorts = append(sorts, sort)
+		}
+	}
+	return sorts
+}
+
+func isPaginationField(field string) bool {
+	if field == "" || len(field) > 64 {
+		return false
+	}
+	for _, c := range field {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '_' || c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// The quoteCurlString function quotes the string as a shell single-quoted
+// argument.
+func quoteCurlString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", `'\''`) + "'"
+}
+
 type contextMessage struct {
 	Time       string `json:"time" protobuf:"1,name=time" yaml:"time"`
 	Host       string `json:"host" protobuf:"2,name=host" yaml:"host"`
@@ -259,7 +345,7 @@ func NewContextMessgae(ctx Context, err error, message any) any {
 		Route:      ctx.GetParam(ParamRoute),
 		XRequestID: h.Get(HeaderXRequestID),
 		XTraceID:   h.Get(HeaderXTraceID),
-		Status:     ctx.Response().Status(),
+		Status:     ctx.Status(),
 		Message:    message,
 	}
 	if err != nil {
@@ -275,6 +361,11 @@ func getErrorStatus(err error) int {
 		return statusErr.Status()
 	}
 
+	status := getRegisterErrorStatus(err)
+	if status > 0 {
+		return status
+	}
+
 	var maxBytesErr *http.MaxBytesError
 	if errors.As(err, &maxBytesErr) {
 		return StatusRequestEntityTooLarge
@@ -291,6 +382,94 @@ func getErrorCode(err error) int {
 	return 0
 }
 
+func getErrorMessage(err error) any {
+	var messageErr interface{ Message() any }
+	if errors.As(err, &messageErr) {
+		return messageErr.Message()
+	}
+	return nil
+}
+
+// bindError defines the client error of [Context].Bind.
+type bindError struct {
+	err    error
+	status int
+}
+
+// bindErrorMessage defines the Bind error message of json.
+type bindErrorMessage struct {
+	Field  string `json:"field,omitempty" protobuf:"1,name=field" yaml:"field,omitempty"`
+	Offset int64  `json:"offset,omitempty" protobuf:"2,name=offset" yaml:"offset,omitempty"`
+	Type   string `json:"type,omitempty" protobuf:"3,name=type" yaml:"type,omitempty"`
+	Value  string `json:"value,omitempty" protobuf:"4,name=value" yaml:"value,omitempty"`
+}
+
+// The RequireKeys function checks that the keys exist in data and are not nil,
+// the key uses [GetAnyByPath] and can be a path such as 'user.name'.
+//
+// Return the error with [StatusBadRequest] listing all missing keys.
+//
+//	data, err := ctx.BindMap()
+//	if err == nil {
+//		err = eudore.RequireKeys(data, "name", "user.id")
+//	}
+func RequireKeys(data map[string]any, keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if GetAnyByPath(data, key) == nil {
+			missing = append(missing, key)
+		}
+	}
+	if missing == nil {
+		return nil
+	}
+	return NewErrorWithStatus(fmt.Errorf(ErrContextRequireKeysMissing,
+		strings.Join(missing, ", "),
+	), StatusBadRequest)
+}
+
+// The newBindError function wraps the error without status as
+// [DefaultContextBindErrorStatus].
+func newBindError(err error) error {
+	var statusErr interface{ Status() int }
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &statusErr) || errors.As(err, &maxBytesErr) ||
+		DefaultContextBindErrorStatus == 0 {
+		return err
+	}
+	return bindError{err, DefaultContextBindErrorStatus}
+}
+
+func (err bindError) Error() string {
+	return err.err.Error()
+}
+
+func (err bindError) Unwrap() error {
+	return err.err
+}
+
+func (err bindError) Status() int {
+	return err.status
+}
+
+// The Message method returns the field and offset of the json error.
+func (err bindError) Message() any {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err.err, &typeErr):
+		return bindErrorMessage{
+			Field:  typeErr.Field,
+			Offset: typeErr.Offset,
+			Type:   typeErr.Type.String(),
+			Value:  typeErr.Value,
+		}
+	case errors.As(err.err, &syntaxErr):
+		return bindErrorMessage{Offset: syntaxErr.Offset}
+	}
+	return nil
+}
+
 func (ctx *contextBase) wrapLogger() Logger {
 	return ctx.logger().WithField(ParamDepth, 1)
 }
@@ -364,6 +543,10 @@ func (ctx *contextBase) Fatalf(format string, args ...any) {
 	ctx.wrapLogger().Errorf(msg)
 }
 
+func (ctx *contextBase) Logger() Logger {
+	return ctx.logger()
+}
+
 func (ctx *contextBase) WithField(key string, value any) Logger {
 	return &contextBaseEntry{
 		Logger:     ctx.logger().WithField(key, value),
@@ -1,6 +1,7 @@
 package eudore
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
@@ -15,6 +16,7 @@ var (
 	ContextKeyAppCancel       = NewContextKey("app-Cancel")
 	ContextKeyAppValues       = NewContextKey("app-values")
 	ContextKeyLogger          = NewContextKey("logger")
+	ContextKeyLoggerGroup     = NewContextKey("logger-group")
 	ContextKeyConfig          = NewContextKey("config")
 	ContextKeyClient          = NewContextKey("client")
 	ContextKeyClientTrace     = NewContextKey("client-trace")
@@ -25,7 +27,10 @@ var (
 	ContextKeyContextUser     = NewContextKey("context-user")
 	ContextKeyHandlerExtender = NewContextKey("handler-extender")
 	ContextKeyBind            = NewContextKey("handler-bind")
+	ContextKeyBindHook        = NewContextKey("handler-bind-hook")
+	ContextKeyValidate        = NewContextKey("handler-validate")
 	ContextKeyRender          = NewContextKey("handler-render")
+	ContextKeyRenderIndent    = NewContextKey("handler-render-indent")
 	ContextKeyHTTPHandler     = NewContextKey("http-handler")
 	ContextKeyFuncCreator     = NewContextKey("func-creator")
 	ContextKeyFilterRules     = NewContextKey("filter-rules")
@@ -107,9 +112,19 @@ var (
 	// DefaultConfigParseTimeout global defines the [Config.Parse] method
 	// execution timeout.
 	DefaultConfigParseTimeout = time.Second * 60
+	// DefaultConfigSecretValue global defines the value of the field with
+	// tag `secret:"true"` rendered by [NewHandlerConfig].
+	DefaultConfigSecretValue = "******"
+	// DefaultContextBindErrorStatus global defines the status of
+	// [Context].Bind error that does not implement the Status method.
+	DefaultContextBindErrorStatus = StatusBadRequest
 	// DefaultContextMaxHandler global defines the upper limit of the number
 	// of [Context] handlers.
 	DefaultContextMaxHandler = 0xff
+	// DefaultContextSlowHandler global defines the running duration of a
+	// request, after which [App] outputs the [LoggerWarning] log while
+	// the handlers are still running, 0 disables it.
+	DefaultContextSlowHandler = time.Duration(0)
 	// DefaultContextMaxApplicationFormSize defaults to the length limit
 	// of the body when parsing [MimeApplicationForm];
 	// If Body implements Limit() int64 method, this value is ignored.
@@ -117,15 +132,40 @@ var (
 	// DefaultContextMaxMultipartFormMemory The memory size used by the body
 	// when parsing [MimeMultipartForm].
 	DefaultContextMaxMultipartFormMemory int64 = 32 << 20 // 32 MB
+	// DefaultContextCurlBodySize global defines the max body preview size of
+	// [Context].AsCurl.
+	DefaultContextCurlBodySize = 1024
+	// DefaultContextCurlRedactHeaders global defines the headers masked by
+	// [Context].AsCurl.
+	DefaultContextCurlRedactHeaders = []string{
+		HeaderAuthorization, HeaderCookie, HeaderProxyAuthorization,
+	}
+	// DefaultContextCurlRedactValue global defines the masked header value
+	// of [Context].AsCurl.
+	DefaultContextCurlRedactValue = "******"
 	// DefaultContextFormatTime defines the contextMessage Time format.
 	// Modification affects the API response.
 	DefaultContextFormatTime = "2006-01-02 15:04:05.000"
+	// DefaultContextPaginationSize global defines the default size of
+	// [Context].Pagination.
+	DefaultContextPaginationSize = 20
+	// DefaultContextPaginationMaxSize global defines the default max size of
+	// [Context].Pagination.
+	DefaultContextPaginationMaxSize = 100
 	// DefaultControllerParam global defines the controller injection [Params]
 	// format and is replaced using [strings.ReplaceAll].
 	DefaultControllerParam = "controller={{Package}}.{{Name}} controllermethod={{Method}}"
 	// DefaultFuncCreator defines the global default [FuncCreator]
 	// used by [NewRouterCoreMux].
 	DefaultFuncCreator = NewFuncCreator()
+	// DefaultHandlerDataBindBufferSize global defines the max capacity of
+	// the pooled buffer used by [HandlerDataBindJSON] to read body,
+	// the larger buffer is not reused, if it is 0, disable buffer pool.
+	DefaultHandlerDataBindBufferSize = 64 << 10 // 64 KB
+	// DefaultHandlerDataBindDecompressSize global defines the max size of
+	// the decompressed body when [NewHandlerDataBinds] read
+	// [HeaderContentEncoding] gzip or deflate body.
+	DefaultHandlerDataBindDecompressSize int64 = 32 << 20 // 32 MB
 	// DefaultHandlerDataBindFormTags global defines the form tags
 	// for [HandlerDataBindForm].
 	DefaultHandlerDataBindFormTags = []string{"form", "alias"}
@@ -141,10 +181,19 @@ var (
 		MimeMultipartForm:          HandlerDataBindForm,
 		MimeApplicationProtobuf:    HandlerDataBindProtobuf,
 		MimeApplicationXML:         HandlerDataBindXML,
+		MimeApplicationGob:         HandlerDataBindGob,
+		MimeApplicationMsgpack:     HandlerDataBindMsgpack,
 	}
+	// DefaultHandlerDataRenderCSVTags global defines the header tags
+	// for [HandlerDataRenderCSV].
+	DefaultHandlerDataRenderCSVTags = []string{"alias", "json"}
 	// DefaultHandlerDataRenderFunc defines the default Render function used
 	// by [NewHandlerDataRenders].
 	DefaultHandlerDataRenderFunc = HandlerDataRenderJSON
+	// DefaultHandlerDataRenderJSONStreamSize global defines the slice length
+	// of [HandlerDataRenderJSON] to stream elements, and the number of
+	// elements between each Flush.
+	DefaultHandlerDataRenderJSONStreamSize = 1024
 	// DefaultHandlerDataRenders defines all [HandlerDataFuncs] processed
 	// by [NewHandlerDataRenders].
 	DefaultHandlerDataRenders = map[string]HandlerDataFunc{
@@ -153,6 +202,9 @@ var (
 		MimeTextHTML:            NewHandlerDataRenderTemplates(nil, nil),
 		MimeApplicationJSON:     HandlerDataRenderJSON,
 		MimeApplicationProtobuf: HandlerDataRenderProtobuf,
+		MimeApplicationGob:      HandlerDataRenderGob,
+		MimeApplicationMsgpack:  HandlerDataRenderMsgpack,
+		MimeTextCSV:             HandlerDataRenderCSV,
 	}
 	// DefaultHandlerDataRenderTemplateAppend defines the non-existent template
 	// to be append when Render the template.
@@ -170,6 +222,10 @@ var (
 	// DefaultHandlerDataTemplateReload defines
 	// [NewHandlerDataRenderTemplates] enables template Reload.
 	DefaultHandlerDataTemplateReload = true
+	// DefaultHandlerRenderNoContent defines whether the extended handler
+	// writes [StatusNoContent] when the returned data is nil and
+	// the status is not set, otherwise Render the nil data.
+	DefaultHandlerRenderNoContent = false
 	// DefaultHandlerValidateTag global defines the struct tag of
 	// [NewHandlerDataValidateStruct] to get the validation rules.
 	DefaultHandlerValidateTag = "valid"
@@ -208,6 +264,8 @@ var (
 		NewHandlerFuncContextAny,
 		NewHandlerFuncContextError,
 		NewHandlerFuncContextAnyError,
+		NewHandlerFuncContextBytes,
+		NewHandlerFuncContextStringError,
 		NewHandlerFuncContextMapAnyError,
 		NewHandlerHTTPFunc1,
 		NewHandlerHTTPFunc2,
@@ -216,6 +274,30 @@ var (
 		NewHandlerFileSystem,
 		NewHandlerAnyContextTypeAnyError,
 	}
+	// DefaultJSONMarshal global defines the json marshal function used by
+	// [HandlerDataRenderJSON] and [Logger] to encode [json.Marshaler],
+	// can be replaced with a faster json library.
+	DefaultJSONMarshal = json.Marshal
+	// DefaultJSONUnmarshal global defines the json unmarshal function used by
+	// [HandlerDataBindJSON].
+	DefaultJSONUnmarshal = json.Unmarshal
+	// DefaultMsgpackMarshal and DefaultMsgpackUnmarshal global define the
+	// msgpack functions used by [HandlerDataRenderMsgpack] and
+	// [HandlerDataBindMsgpack], which are nil by default to avoid
+	// dependencies, e.g. set to msgpack.Marshal and msgpack.Unmarshal.
+	DefaultMsgpackMarshal   func(any) ([]byte, error)
+	DefaultMsgpackUnmarshal func([]byte, any) error
+	// DefaultLoggerCallerCache defines whether [GetCallerFuncFile] caches
+	// the func name and file line by the caller pc.
+	DefaultLoggerCallerCache = true
+	// DefaultLoggerCallerTrimPrefix defines the file path prefix trimmed by
+	// [GetCallerFuncFile] and [GetCallerStacks], such as the module root
+	// directory "/home/user/project/", the file path is then relative to
+	// the module.
+	//
+	// If empty or not matched, trim the path before "/pkg/mod/" or "/src/".
+	DefaultLoggerCallerTrimPrefix = ""
+	// DefaultLoggerDepthKind defines the kind values of [ParamDepth].
 	DefaultLoggerDepthKindEnable  = "enable"
 	DefaultLoggerDepthKindDisable = "disable"
 	DefaultLoggerDepthKindStack   = "stack" // non-fixed
@@ -233,12 +315,26 @@ var (
 	DefaultLoggerFormatter = "json"
 	// DefaultLoggerFormatterFormatTime defines the time format for log output.
 	DefaultLoggerFormatterFormatTime = "2006-01-02 15:04:05.000"
+	// DefaultLoggerFormatterFloatPrecision defines the number of digits after
+	// the decimal point for float output, -1 uses the smallest number
+	// of digits necessary.
+	DefaultLoggerFormatterFloatPrecision = -1
 	// DefaultLoggerFormatterKeyLevel defines the level field output name.
 	DefaultLoggerFormatterKeyLevel = "level"
 	// DefaultLoggerFormatterKeyMessage defines the message field output name.
 	DefaultLoggerFormatterKeyMessage = "message"
 	// DefaultLoggerFormatterKeyTime defines the Time field output name.
 	DefaultLoggerFormatterKeyTime = "time"
+	// DefaultLoggerFormatterOmitEmptyStruct defines whether the json
+	// formatter omits the omitempty struct field that is encoded as {}.
+	DefaultLoggerFormatterOmitEmptyStruct = false
+	// DefaultLoggerFormatterTags defines the struct field tags used by the
+	// json formatter for field names and omitempty, the first existing
+	// tag is used.
+	//
+	// The struct encoder is cached by type,
+	// modification only affects types that have not been logged.
+	DefaultLoggerFormatterTags = []string{"json"}
 	// DefaultLoggerHookFatal defines whether HookFatal is enabled by default.
 	DefaultLoggerHookFatal = false
 	// DefaultLoggerLevelStrings global defines the log level output strings.
@@ -247,6 +343,14 @@ var (
 	DefaultLoggerLevelStrings = [...]string{
 		"DEBUG", "INFO", "WARNING", "ERROR", "FATAL", "DISCARD",
 	}
+	// DefaultLoggerWriterNetworkBackoffMin and
+	// DefaultLoggerWriterNetworkBackoffMax global define the reconnect
+	// backoff range of [NewLoggerWriterNetwork].
+	DefaultLoggerWriterNetworkBackoffMin = 100 * time.Millisecond
+	DefaultLoggerWriterNetworkBackoffMax = 30 * time.Second
+	// DefaultLoggerWriterNetworkTimeout global defines the dial and write
+	// timeout of [NewLoggerWriterNetwork].
+	DefaultLoggerWriterNetworkTimeout = 3 * time.Second
 	// DefaultLoggerWriterRotateDataKeys global defines the keywords for
 	// date rolling time/day/month/year, the order cannot be changed.
 	DefaultLoggerWriterRotateDataKeys = [...]string{"hh", "dd", "mm", "yyyy"}
@@ -260,13 +364,16 @@ var (
 	DefaultLoggerPriorityInit      = 100
 	// DefaultLoggerPriorityFormatter defines the log formatter priority.
 	// Text and JSON share this value.
-	DefaultLoggerPriorityFormatter    = 30
-	DefaultLoggerPriorityHookFatal    = 101
-	DefaultLoggerPriorityHookFilter   = 10
-	DefaultLoggerPriorityHookMeta     = 60
-	DefaultLoggerPriorityWriterAsync  = 80
-	DefaultLoggerPriorityWriterStdout = 90
-	DefaultLoggerPriorityWriterFile   = 100
+	DefaultLoggerPriorityFormatter     = 30
+	DefaultLoggerPriorityHookFatal     = 101
+	DefaultLoggerPriorityHookFilter    = 10
+	DefaultLoggerPriorityHookMeta      = 60
+	DefaultLoggerPriorityHookRepeat    = 20
+	DefaultLoggerPriorityWriterAsync   = 80
+	DefaultLoggerPriorityWriterStdout  = 90
+	DefaultLoggerPriorityWriterFile    = 100
+	DefaultLoggerPriorityWriterRing    = 100
+	DefaultLoggerPriorityWriterNetwork = 100
 	// DefaultRouterAllMethod defines all methods that the router is allowed.
 	//
 	// Used global in [ControllerInjectAutoRoute].
@@ -280,6 +387,22 @@ var (
 	// DefaultRouterLoggerKind defines the types of logs that the Router
 	// outputs.
 	DefaultRouterLoggerKind = "all"
+	// DefaultRouterMiddlewarePhases defines the named priority used by
+	// [ParamPriority] when the Router adds middleware.
+	//
+	// Middleware with smaller priority is executed first,
+	// and the default phase is route.
+	DefaultRouterMiddlewarePhases = map[string]int{
+		"logger":   10,
+		"recovery": 20,
+		"auth":     30,
+		"route":    50,
+	}
+	// DefaultRouterSuggestion global defines whether the 404 response
+	// contains the nearest registered routes, used during development.
+	//
+	// The route is suggested when only one constant segment is different.
+	DefaultRouterSuggestion = false
 	// DefaultServerListen defines [ServerListenConfig] to use the
 	// [net.Listen] function for hooking listen.
 	DefaultServerListen            = net.Listen
@@ -295,6 +418,14 @@ var (
 	// DefaultValueGetSetTags global defines the tags for
 	// [GetAnyByPath]/[SetAnyByPath].
 	DefaultValueGetSetTags = []string{"alias"} // non-fixed
+	// DefaultValueParseBoolLiterals global defines the extra bool literals
+	// parsed before [strconv.ParseBool], case insensitive.
+	//
+	// Set to nil to only use [strconv.ParseBool].
+	DefaultValueParseBoolLiterals = map[string]bool{ // non-fixed
+		"yes": true, "on": true,
+		"no": false, "off": false,
+	}
 	// DefaultValueParseTimeFormats global defines the time formats to attempt
 	// parse in [GetAnyByString].
 	DefaultValueParseTimeFormats = []string{ // non-fixed
@@ -317,11 +448,19 @@ var (
 	// DefaultGodocServer defines the godoc server domain name used by the app.
 	DefaultGodocServer = "https://golang.org"
 
+	ErrAppScheduleInvalid  = "App: schedule spec '%s' is invalid"
+	ErrAppSlowHandler      = errors.New("App: handler is still running after slow duration")
+	ErrAppWaitTasksTimeout = errors.New("App: wait background tasks timeout")
+
+	ErrLoggerEncoderPanic       = "<panic: %v>"
 	ErrLoggerLevelUnmarshalText = "LoggerLevel: UnmarshalText invalid data: %s"
+	ErrLoggerMarshalJSONInvalid = "Logger: MarshalJSON for type %s returned invalid json: %w"
 	ErrLoggerInitUnmounted      = errors.New("Logger: loggerInit has been Unmounted, please check the logger initialization order")
+	ErrLoggerReloadDisabled     = errors.New("Logger: reload is disabled, LoggerConfig.Reload must be true")
 
-	ErrConfigParseDecoder = "Config: decoder %s parse file '%s' error: %w"
-	ErrConfigParseError   = "Config: parse func %v error: %v"
+	ErrConfigParseDecoder         = "Config: decoder %s parse file '%s' error: %w"
+	ErrConfigParseError           = "Config: parse func %v error: %v"
+	ErrConfigParseExpandUndefined = "Config: expand undefined variable '%s'"
 
 	ErrRouterAddController              = "Router: AddController inject %s error: %w"
 	ErrRouterAddHandlerExtender         = "Router: AddHandlerExtender path is '%s' RegisterHandlerExtender error: %w"
@@ -329,6 +468,7 @@ var (
 	ErrRouterAddHandlerRecover          = "Router: addHandler method is '%s' and path is '%s', recover error: %w"
 	ErrRouterHandlerFuncsUnregisterType = "Router: newHandlerFuncs path is '%s', %dth handler parameter type is '%s', this is the unregistered handler type"
 	ErrRouterMuxLoadInvalidFunc         = "routerCoreMux: load path '%s' is invalid, error: %w"
+	ErrRouterPathParamUndefined         = "RoutePath: param '%s' is not defined in path '%s'"
 
 	ErrClientBodyNotGetBody    = errors.New("ClientBody: cannot copy body")
 	ErrClientOptionInvalidType = "ClientOption: invalid option type %T"
@@ -338,10 +478,18 @@ var (
 
 	ErrContextParseFormNotSupportContentType = "Context: parse form not support Content-Type: %s"
 	ErrContextRedirectInvalid                = "Context: invalid redirect status code %d"
+	ErrContextRequireKeysMissing             = "Context: missing required keys %s"
+	ErrContextRedirectUnsafe                 = "Context: unsafe redirect url %s"
+	ErrContextTrailerNotSupport              = "Context: trailer %s is not supported by the response"
+	ErrContextWriteAfterEnd                  = "Context: discard %d bytes written after End"
 	ErrContextNotHijacker                    = errors.New("ResponseWriter: http.Hijacker interface is not supported")
 
 	ErrHandlerDataBindNotSupportContentType = "HandlerData bind: not support Content-Type: %s"
+	ErrHandlerDataBindNotSupportEncoding    = "HandlerData bind: not support Content-Encoding: %s"
 	ErrHandlerDataBindMustSturct            = "HandlerData bind: value type %s must be a struct"
+	ErrHandlerDataMsgpackNotSet             = errors.New("HandlerData: DefaultMsgpackMarshal or DefaultMsgpackUnmarshal is not set")
+	ErrHandlerDataRenderCSVType             = "HandlerData render: csv data type %s must be slice of struct or map"
+	ErrHandlerDataRenderJSONPCallback       = errors.New("HandlerData render: invalid jsonp callback name")
 	ErrHandlerDataRenderTemplateNotFound    = "HandlerData render: not found template %s"
 	ErrHandlerDataRenderTemplateNotLoad     = "Unable to load template at %s: patterns: %v"
 	ErrHandlerDataRenderTemplateNeedName    = errors.New("HandlerData render: template need eudore.Context param 'template'")
@@ -349,6 +497,7 @@ var (
 	ErrHandlerDataValidateCreateRule        = "Validate: %s.%s field %s create rule %s error: %w"
 
 	ErrHandlerExtenderParamNotFunc = errors.New("HandlerExtender: registration function must be a function type")
+	ErrHandlerExtenderParamType    = "%w, current type is %T"
 	ErrHandlerExtenderInputParam   = "HandlerExtender: parameter kind of the registered function %s must be one of func/interface/ptr/struct "
 	ErrHandlerExtenderOutputParam  = "HandlerExtender: return type of the registered function %s must be of HandlerFunc type"
 	ErrHandlerFuncsCombineTooMany  = "NewHandlerFuncsCombine: too many handlers %d"
@@ -381,6 +530,8 @@ var (
 	ErrFormatValueMapValueInvalid   = "get index '%s' value is invalid"
 	ErrFormatValueStructUnexported  = "field '%s' is unexported"
 	ErrFormatValueStructNotCanset   = "field '%s' is not canset "
+	// ErrFormatValueParseNumber defines the number parsing error of a field.
+	ErrFormatValueParseNumber = "parse '%s' to %s: %w"
 	// ErrFormatConverterSetStringUnknownType setWithString函数遇到未定义的反射类型。
 	ErrFormatValueSetStringUnknownType = "setWithString unknown type %s"
 	// ErrFormatConverterSetWithValue setWithValue函数中类型无法赋值。
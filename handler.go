@@ -6,12 +6,14 @@ import (
 	iofs "io/fs"
 	"math"
 	"net/http"
+	"net/url"
 	"os"
 	filepath "path"
 	"reflect"
 	"runtime"
 	"sort"
 	"strconv"
+	"strings"
 	"unsafe"
 )
 
@@ -35,9 +37,17 @@ func HandlerRouter403(ctx Context) {
 }
 
 // HandlerRouter404 function defines the [StatusNotFound] processing.
+//
+// If [DefaultRouterSuggestion] is enabled, the response contains
+// the nearest routes of [ParamRouteSuggest].
 func HandlerRouter404(ctx Context) {
 	const page404 = "404 Not Found"
 	ctx.WriteStatus(StatusNotFound)
+	suggest := ctx.GetParam(ParamRouteSuggest)
+	if suggest != "" {
+		_ = ctx.Render(page404 + ", did you mean: " + suggest)
+		return
+	}
 	_ = ctx.Render(page404)
 }
 
@@ -51,6 +61,63 @@ func HandlerRouter405(ctx Context) {
 	_ = ctx.Render(page405)
 }
 
+// The NewHandlerRouter404 function creates the [StatusNotFound] processing
+// that uses render to Render the response body.
+//
+// The data has status, message, path and suggest fields, and implements
+// [fmt.Stringer] to return "404 Not Found".
+// If render is nil, use ctx.Render.
+func NewHandlerRouter404(render HandlerDataFunc) HandlerFunc {
+	render = getHandlerRouterRender(render)
+	return func(ctx Context) {
+		ctx.WriteStatus(StatusNotFound)
+		_ = render(ctx, newRouterStatus(ctx, StatusNotFound))
+	}
+}
+
+// The NewHandlerRouter405 function creates the [StatusMethodNotAllowed]
+// processing that uses render to Render the response body.
+//
+// refer: [NewHandlerRouter404] [HandlerRouter405].
+func NewHandlerRouter405(render HandlerDataFunc) HandlerFunc {
+	render = getHandlerRouterRender(render)
+	return func(ctx Context) {
+		ctx.SetHeader(HeaderAllow, ctx.GetParam(ParamAllow))
+		ctx.SetHeader(HeaderXEudoreRoute, ctx.GetParam(ParamRoute))
+		ctx.WriteStatus(StatusMethodNotAllowed)
+		_ = render(ctx, newRouterStatus(ctx, StatusMethodNotAllowed))
+	}
+}
+
+func getHandlerRouterRender(render HandlerDataFunc) HandlerDataFunc {
+	if render == nil {
+		return func(ctx Context, data any) error {
+			return ctx.Render(data)
+		}
+	}
+	return render
+}
+
+type routerStatus struct {
+	Status  int    `json:"status" protobuf:"1,name=status" yaml:"status"`
+	Message string `json:"message" protobuf:"2,name=message" yaml:"message"`
+	Path    string `json:"path" protobuf:"3,name=path" yaml:"path"`
+	Suggest string `json:"suggest,omitempty" protobuf:"4,name=suggest" yaml:"suggest,omitempty"`
+}
+
+func newRouterStatus(ctx Context, status int) *routerStatus {
+	return &routerStatus{
+		Status:  status,
+		Message: http.StatusText(status),
+		Path:    ctx.Path(),
+		Suggest: ctx.GetParam(ParamRouteSuggest),
+	}
+}
+
+func (s *routerStatus) String() string {
+	return strconv.Itoa(s.Status) + " " + s.Message
+}
+
 // The NewHandlerFuncsFilter function filters out nil objects in [HandlerFuncs].
 func NewHandlerFuncsFilter(hs HandlerFuncs) HandlerFuncs {
 	var size int
@@ -103,11 +170,18 @@ type reflectValue struct {
 	flag uintptr
 }
 
+// The funcPointer type saves the closure address and code address of
+// [HandlerFunc].
+type funcPointer [2]uintptr
+
 // The getFuncPointer function get the address of [HandlerFunc]
 // as the unique identifier id.
-func getFuncPointer(v reflect.Value) uintptr {
+//
+// The address of a freed closure may be reused by other closure,
+// the code address is used to avoid matching the stale name.
+func getFuncPointer(v reflect.Value) funcPointer {
 	val := *(*reflectValue)(unsafe.Pointer(&v))
-	return val.ptr
+	return funcPointer{val.ptr, v.Pointer()}
 }
 
 // The SetHandlerAliasName function sets the original name of extension object.
@@ -181,6 +255,9 @@ func NewHandlerFileSystems(dirs ...any) HandlerFunc {
 
 // The NewHandlerFileEmbed function creates the [iofs.FS] extension function.
 //
+// Any [iofs.FS] value such as [embed.FS] or fstest.MapFS can be registered
+// as a route handler, the file path is the route wildcard param.
+//
 // refer [NewHandlerFileSystem].
 func NewHandlerFileEmbed(fs iofs.FS) HandlerFunc {
 	return NewHandlerFileSystem(NewFileSystems(fs))
@@ -189,15 +266,20 @@ func NewHandlerFileEmbed(fs iofs.FS) HandlerFunc {
 // The NewHandlerFileSystem function creates an [http.FileSystem] extension
 // function.
 //
-// Open the file path as [ParamPrefix] join ctx.GetParam("*").
+// Open the file path as [ParamPrefix] join the wildcard param,
+// the wildcard param reads [ParamWildcard] and then '*',
+// route can use '/static/*path' or '/static/*'.
 //
 // If the file is a directory and [ParamAutoIndex] is true,
 // display the directory index page.
+//
+// If [ParamAttachment] is true, the file is responded as an attachment.
 func NewHandlerFileSystem(fs http.FileSystem) HandlerFunc {
 	embedTime := DefaultHandlerEmbedTime
 	cacheControl := DefaultHandlerEmbedCacheControl
 	return func(ctx Context) {
-		path := filepath.Join(ctx.GetParam(ParamPrefix), ctx.GetParam("*"))
+		wildcard := getWildcardParam(ctx)
+		path := filepath.Join(ctx.GetParam(ParamPrefix), wildcard)
 		if path == "" {
 			path = "."
 		}
@@ -227,18 +309,72 @@ func NewHandlerFileSystem(fs http.FileSystem) HandlerFunc {
 			if w.Header().Get(HeaderCacheControl) == "" {
 				w.Header().Add(HeaderCacheControl, cacheControl)
 			}
+			if GetAnyByString[bool](ctx.GetParam(ParamAttachment)) {
+				ctx.Attachment(stat.Name())
+			}
 			http.ServeContent(w, ctx.Request(), stat.Name(), modtime, file)
 		case GetAnyByString[bool](ctx.GetParam(ParamAutoIndex)):
 			h := ctx.Response().Header()
 			h.Set(HeaderCacheControl, "no-cache")
 			h.Set(HeaderLastModified, modtime.UTC().Format(http.TimeFormat))
-			handlerStaticDirs(ctx, "/"+ctx.GetParam("*"), file)
+			handlerStaticDirs(ctx, "/"+wildcard, file)
 		default:
 			ctx.WriteHeader(StatusNotFound)
 		}
 	}
 }
 
+func getWildcardParam(ctx Context) string {
+	path := ctx.GetParam(ParamWildcard)
+	if path == "" {
+		path = ctx.GetParam("*")
+	}
+	return path
+}
+
+// The NewHandlerMount function creates [HandlerFunc] to mount [http.Handler]
+// under the prefix, like [http.StripPrefix] strips the prefix of the
+// request path and then calls h.
+//
+// If prefix is empty, the request path is '/' join the wildcard param,
+// route can use '/api/*path' or '/api/*'.
+//
+// If the request path does not have the prefix, respond with 404.
+//
+//	app.AnyFunc("/api/*", eudore.NewHandlerMount("/api", mux))
+func NewHandlerMount(prefix string, h http.Handler) HandlerFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(ctx Context) {
+		r := ctx.Request()
+		path, rawpath := r.URL.Path, r.URL.RawPath
+		if prefix == "" {
+			path, rawpath = getWildcardParam(ctx), ""
+		} else {
+			var ok bool
+			path, ok = strings.CutPrefix(path, prefix)
+			if !ok {
+				HandlerRouter404(ctx)
+				return
+			}
+			rawpath = strings.TrimPrefix(rawpath, prefix)
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+			if rawpath != "" {
+				rawpath = "/" + rawpath
+			}
+		}
+
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = path
+		r2.URL.RawPath = rawpath
+		h.ServeHTTP(ctx.Response(), r2)
+	}
+}
+
 type fileInfo struct {
 	Name       string `json:"name" protobuf:"1,name" yaml:"name"`
 	Size       int64  `json:"size" protobuf:"2,size"  yaml:"size"`
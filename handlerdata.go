@@ -1,16 +1,25 @@
 package eudore
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/csv"
+	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"html/template"
+	"io"
 	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // HandlerDataFunc defines the [Context] data processing function.
@@ -64,14 +73,50 @@ func NewHandlerDataStatusCode(handler HandlerDataFunc, status, code int,
 	}
 }
 
+// The NewHandlerDataRenderEnvelope function wraps the data of
+// [HandlerDataFunc] in the {"code":0,"data":...,"msg":"ok"} envelope.
+//
+// The error message created by [NewContextMessgae] uses the error Code or
+// response Status as code, and the error as msg.
+//
+// Use app.SetValue([ContextKeyRender], NewHandlerDataRenderEnvelope(nil))
+// to install for [App].
+func NewHandlerDataRenderEnvelope(render HandlerDataFunc) HandlerDataFunc {
+	if render == nil {
+		render = NewHandlerDataRenders(nil)
+	}
+	return func(ctx Context, data any) error {
+		env := handlerDataEnvelope{Data: data, Msg: "ok"}
+		msg, ok := data.(contextMessage)
+		if ok {
+			env.Code, env.Data, env.Msg = msg.Code, msg.Message, msg.Error
+			if env.Code == 0 {
+				env.Code = msg.Status
+			}
+		}
+		return render(ctx, env)
+	}
+}
+
+type handlerDataEnvelope struct {
+	Code int    `json:"code" protobuf:"1,name=code" yaml:"code"`
+	Data any    `json:"data,omitempty" protobuf:"2,name=data" yaml:"data,omitempty"`
+	Msg  string `json:"msg" protobuf:"3,name=msg" yaml:"msg"`
+}
+
 // The NewHandlerDataBinds method defines the [HeaderContentType] mapping
 // Bind function.
 //
 // [DefaultHandlerDataBinds] is used by default.
-// [HandlerDataBindURL] is used when [HeaderContentType] is empty.
+// [HandlerDataBindURL] is used when [HeaderContentType] is empty
+// or the request has no body, binding an empty body leaves the defaults.
 //
 // If there is no matching [HandlerDataFunc],
 // return [StatusUnsupportedMediaType].
+//
+// If [HeaderContentEncoding] is gzip or deflate, the body is decompressed
+// and limited to [DefaultHandlerDataBindDecompressSize],
+// exceeding the limit returns [http.MaxBytesError].
 func NewHandlerDataBinds(binds map[string]HandlerDataFunc) HandlerDataFunc {
 	if binds == nil {
 		binds = mapClone(DefaultHandlerDataBinds)
@@ -87,9 +132,18 @@ func NewHandlerDataBinds(binds map[string]HandlerDataFunc) HandlerDataFunc {
 	}
 	mimes = strings.TrimPrefix(mimes, ", ")
 	return func(ctx Context, data any) error {
+		r := ctx.Request()
+		if r.ContentLength == 0 || r.Body == nil || r.Body == http.NoBody {
+			return binds[""](ctx, data)
+		}
+
 		contentType := ctx.GetHeader(HeaderContentType)
 		fn, ok := binds[strings.SplitN(contentType, ";", 2)[0]]
 		if ok {
+			err := bindDecompress(ctx)
+			if err != nil {
+				return err
+			}
 			return fn(ctx, data)
 		}
 
@@ -105,6 +159,45 @@ func NewHandlerDataBinds(binds map[string]HandlerDataFunc) HandlerDataFunc {
 	}
 }
 
+// The bindDecompress function wraps the request body with a decompressor
+// using [HeaderContentEncoding].
+func bindDecompress(ctx Context) error {
+	r := ctx.Request()
+	encoding := strings.ToLower(strings.TrimSpace(
+		r.Header.Get(HeaderContentEncoding),
+	))
+	var reader io.ReadCloser
+	switch encoding {
+	case "", "identity":
+		return nil
+	case "gzip", "x-gzip":
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return NewErrorWithStatus(err, StatusBadRequest)
+		}
+		reader = gr
+	case "deflate":
+		zr, err := zlib.NewReader(r.Body)
+		if err != nil {
+			return NewErrorWithStatus(err, StatusBadRequest)
+		}
+		reader = zr
+	default:
+		err := fmt.Errorf(ErrHandlerDataBindNotSupportEncoding, encoding)
+		return NewErrorWithStatus(err, StatusUnsupportedMediaType)
+	}
+
+	limit := DefaultHandlerDataBindDecompressSize
+	if limit > 0 {
+		reader = http.MaxBytesReader(ctx.Response(), reader, limit)
+	}
+	// body has been decompressed, avoid decompressing again.
+	r.Header.Del(HeaderContentEncoding)
+	r.ContentLength = -1
+	r.Body = reader
+	return nil
+}
+
 func bindMaps[T any](source map[string][]T, target any, tags []string) error {
 	v := reflect.Indirect(reflect.ValueOf(target))
 	switch v.Kind() {
@@ -164,9 +257,44 @@ func HandlerDataBindForm(ctx Context, data any) error {
 	return nil
 }
 
-// The HandlerDataBindJSON function uses [json.NewDecoder] to Bind data.
+// The HandlerDataBindJSON function uses [DefaultJSONUnmarshal] to Bind data.
+//
+// If the body is empty, the data is not modified.
+//
+// The body is read into a buffer pooled by [DefaultHandlerDataBindBufferSize],
+// [DefaultJSONUnmarshal] must not retain the data after returning.
 func HandlerDataBindJSON(ctx Context, data any) error {
-	return json.NewDecoder(ctx).Decode(data)
+	buf := getBindBuffer()
+	defer putBindBuffer(buf)
+	_, err := buf.ReadFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	return DefaultJSONUnmarshal(buf.Bytes(), data)
+}
+
+var bindBufferPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBindBuffer() *bytes.Buffer {
+	if DefaultHandlerDataBindBufferSize > 0 {
+		return bindBufferPool.Get().(*bytes.Buffer)
+	}
+	return new(bytes.Buffer)
+}
+
+func putBindBuffer(buf *bytes.Buffer) {
+	if DefaultHandlerDataBindBufferSize > 0 &&
+		buf.Cap() <= DefaultHandlerDataBindBufferSize {
+		buf.Reset()
+		bindBufferPool.Put(buf)
+	}
 }
 
 // The BindXML function uses [xml.NewDecoder] to Bind data.
@@ -180,6 +308,33 @@ func HandlerDataBindProtobuf(ctx Context, data any) error {
 	return NewProtobufDecoder(ctx).Decode(data)
 }
 
+// The HandlerDataBindGob function uses [gob.NewDecoder] to Bind data.
+func HandlerDataBindGob(ctx Context, data any) error {
+	return gob.NewDecoder(ctx).Decode(data)
+}
+
+// The HandlerDataBindMsgpack function uses [DefaultMsgpackUnmarshal] to
+// Bind data.
+//
+// If [DefaultMsgpackUnmarshal] is nil, return [StatusUnsupportedMediaType].
+func HandlerDataBindMsgpack(ctx Context, data any) error {
+	if DefaultMsgpackUnmarshal == nil {
+		return NewErrorWithStatus(ErrHandlerDataMsgpackNotSet,
+			StatusUnsupportedMediaType,
+		)
+	}
+	buf := getBindBuffer()
+	defer putBindBuffer(buf)
+	_, err := buf.ReadFrom(ctx)
+	if err != nil {
+		return err
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	return DefaultMsgpackUnmarshal(buf.Bytes(), data)
+}
+
 // The NewHandlerDataRenders method uses [HeaderAccept] to matching for
 // Render functions in renders.
 // [DefaultHandlerDataRenders] is used by default.
@@ -246,21 +401,322 @@ func HandlerDataRenderText(ctx Context, data any) error {
 	return err
 }
 
-// The HandlerDataRenderJSON function uses [json.NewEncoder] to Render data.
+// The HandlerDataRenderJSON function uses [DefaultJSONMarshal] to Render data.
+//
+// If [HeaderAccept] is not [MimeApplicationJSON], or the uri parameter
+// 'pretty' is true, or [ContextKeyRenderIndent] value is true,
+// use json indent for output.
 //
-// If [HeaderAccept] is not [MimeApplicationJSON], use json indent for output.
+// If data is a channel, or a slice longer than
+// [DefaultHandlerDataRenderJSONStreamSize] and not implements
+// [json.Marshaler] or [encoding.TextMarshaler], the elements are encoded
+// and flushed incrementally to bound memory,
+// the channel is read until it is closed or the [Context] is done.
 func HandlerDataRenderJSON(ctx Context, data any) error {
+	indent, _ := ctx.Value(ContextKeyRenderIndent).(bool)
+	return renderJSON(ctx, data, indent ||
+		GetAnyByString[bool](ctx.GetQuery("pretty")) ||
+		!strings.Contains(ctx.GetHeader(HeaderAccept), MimeApplicationJSON),
+	)
+}
+
+// The HandlerDataRenderJSONIndent function uses [DefaultJSONMarshal] to
+// Render data with indent.
+func HandlerDataRenderJSONIndent(ctx Context, data any) error {
+	return renderJSON(ctx, data, true)
+}
+
+var regJSONPCallback = regexp.MustCompile(
+	`^[a-zA-Z_$][\w$]*(\.[a-zA-Z_$][\w$]*)*$`,
+)
+
+// The HandlerDataRenderJSONP function uses the uri parameter 'callback' to
+// Render JSONP data, and uses [HandlerDataRenderJSON] if callback is empty.
+//
+// The callback must be a JavaScript identifier with a maximum of 128
+// characters, otherwise write [StatusBadRequest] json error and return
+// [ErrHandlerDataRenderJSONPCallback].
+func HandlerDataRenderJSONP(ctx Context, data any) error {
+	callback := ctx.GetQuery("callback")
+	if callback == "" {
+		return HandlerDataRenderJSON(ctx, data)
+	}
+	if len(callback) > 128 || !regJSONPCallback.MatchString(callback) {
+		err := NewErrorWithStatus(ErrHandlerDataRenderJSONPCallback,
+			StatusBadRequest,
+		)
+		ctx.WriteStatus(StatusBadRequest)
+		_ = renderJSON(ctx, NewContextMessgae(ctx, err, nil), false)
+		return err
+	}
+
+	body, err := DefaultJSONMarshal(data)
+	if err != nil {
+		return err
+	}
+	h := ctx.Response().Header()
+	h.Set(HeaderContentType, MimeApplicationJavascript+"; "+MimeCharsetUtf8)
+	h.Set(HeaderXContentTypeOptions, "nosniff")
+	_, err = fmt.Fprintf(ctx, "/**/%s(%s);", callback, body)
+	return err
+}
+
+func renderJSON(ctx Context, data any, indent bool) error {
 	renderSetContentType(ctx, MimeApplicationJSONCharsetUtf8)
-	switch reflect.Indirect(reflect.ValueOf(data)).Kind() {
-	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	iValue := reflect.Indirect(reflect.ValueOf(data))
+	switch iValue.Kind() {
+	case reflect.Slice:
+		if iValue.Len() > DefaultHandlerDataRenderJSONStreamSize &&
+			iValue.Type().Elem().Kind() != reflect.Uint8 &&
+			!isJSONMarshaler(reflect.TypeOf(data)) &&
+			!isJSONMarshaler(iValue.Type()) {
+			return renderJSONStream(ctx, iValue, indent)
+		}
+	case reflect.Chan:
+		if iValue.Type().ChanDir()&reflect.RecvDir != 0 && !iValue.IsNil() {
+			return renderJSONStream(ctx, iValue, indent)
+		}
+		data = NewContextMessgae(ctx, nil, data)
+	case reflect.Struct, reflect.Map, reflect.Array:
 	default:
 		data = NewContextMessgae(ctx, nil, data)
 	}
-	encoder := json.NewEncoder(ctx)
-	if !strings.Contains(ctx.GetHeader(HeaderAccept), MimeApplicationJSON) {
-		encoder.SetIndent("", "\t")
+	body, err := DefaultJSONMarshal(data)
+	if err != nil {
+		return err
+	}
+	if indent {
+		buf := bytes.NewBuffer(make([]byte, 0, len(body)*2))
+		err = json.Indent(buf, body, "", "\t")
+		if err != nil {
+			return err
+		}
+		body = buf.Bytes()
+	}
+	_, err = ctx.Write(append(body, '\n'))
+	return err
+}
+
+// The isJSONMarshaler function checks whether the type or its pointer
+// implements [json.Marshaler] or [encoding.TextMarshaler].
+func isJSONMarshaler(t reflect.Type) bool {
+	for _, t := range [...]reflect.Type{t, reflect.PointerTo(t)} {
+		if t.Implements(typeJSONMarshaler) || t.Implements(typeTextMarshaler) {
+			return true
+		}
 	}
-	return encoder.Encode(data)
+	return false
+}
+
+// The renderJSONStream function writes the slice or channel elements as a
+// json array.
+//
+// The slice elements are encoded into the buffer and written every
+// [DefaultHandlerDataRenderJSONStreamSize] elements,
+// the channel elements are written one by one.
+// If the element fails to encode before the first write, no data is written,
+// otherwise the response is truncated and the error is returned.
+func renderJSONStream(ctx Context, iValue reflect.Value, indent bool) error {
+	buf := bytes.NewBuffer(nil)
+	sep := []byte(",")
+	if indent {
+		sep = []byte(",\n\t")
+	}
+	isChan := iValue.Kind() == reflect.Chan
+	num := 0
+	write := func(v reflect.Value) error {
+		body, err := DefaultJSONMarshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		switch {
+		case num == 0 && indent:
+			buf.WriteString("[\n\t")
+		case num == 0:
+			buf.WriteByte('[')
+		default:
+			buf.Write(sep)
+		}
+		if indent {
+			err = json.Indent(buf, body, "\t", "\t")
+			if err != nil {
+				return err
+			}
+		} else {
+			buf.Write(body)
+		}
+		num++
+		if !isChan && num%DefaultHandlerDataRenderJSONStreamSize != 0 {
+			return nil
+		}
+		_, err = ctx.Write(buf.Bytes())
+		buf.Reset()
+		if err == nil {
+			ctx.Response().Flush()
+		}
+		return err
+	}
+
+	if iValue.Kind() == reflect.Chan {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: iValue},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		for {
+			chosen, v, ok := reflect.Select(cases)
+			if chosen == 1 {
+				return ctx.Err()
+			}
+			if !ok {
+				break
+			}
+			if err := write(v); err != nil {
+				return err
+			}
+		}
+	} else {
+		for i := 0; i < iValue.Len(); i++ {
+			if err := write(iValue.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch {
+	case num == 0:
+		buf.WriteString("[]\n")
+	case indent:
+		buf.WriteString("\n]\n")
+	default:
+		buf.WriteString("]\n")
+	}
+	_, err := ctx.Write(buf.Bytes())
+	return err
+}
+
+// The HandlerDataRenderCSV function Render slice of struct or map as csv.
+//
+// The header row uses the struct field tag [DefaultHandlerDataRenderCSVTags]
+// or the sorted keys of the first map, values are converted using
+// [GetStringByAny], and nested struct, map and slice values are encoded
+// using [DefaultJSONMarshal].
+func HandlerDataRenderCSV(ctx Context, data any) error {
+	iValue := reflect.Indirect(reflect.ValueOf(data))
+	if !iValue.IsValid() {
+		return fmt.Errorf(ErrHandlerDataRenderCSVType, reflect.TypeOf(data))
+	}
+	if iValue.Kind() != reflect.Slice && iValue.Kind() != reflect.Array {
+		return fmt.Errorf(ErrHandlerDataRenderCSVType, iValue.Type())
+	}
+
+	var names []string
+	var getRow func(reflect.Value) []any
+	iType := iValue.Type().Elem()
+	for iType.Kind() == reflect.Ptr {
+		iType = iType.Elem()
+	}
+	switch iType.Kind() {
+	case reflect.Struct:
+		var fields []int
+		for i := 0; i < iType.NumField(); i++ {
+			name := getRenderCSVName(iType.Field(i))
+			if name != "" {
+				names = append(names, name)
+				fields = append(fields, i)
+			}
+		}
+		getRow = func(v reflect.Value) []any {
+			row := make([]any, len(fields))
+			for i, field := range fields {
+				row[i] = v.Field(field).Interface()
+			}
+			return row
+		}
+	case reflect.Map:
+		if iValue.Len() > 0 {
+			v := reflect.Indirect(iValue.Index(0))
+			for _, key := range v.MapKeys() {
+				names = append(names, GetStringByAny(key.Interface()))
+			}
+			sort.Strings(names)
+		}
+		keyType := iType.Key()
+		getRow = func(v reflect.Value) []any {
+			row := make([]any, len(names))
+			for i, name := range names {
+				key := reflect.ValueOf(name)
+				if key.Type().ConvertibleTo(keyType) {
+					val := v.MapIndex(key.Convert(keyType))
+					if val.IsValid() {
+						row[i] = val.Interface()
+					}
+				}
+			}
+			return row
+		}
+	default:
+		return fmt.Errorf(ErrHandlerDataRenderCSVType, iValue.Type())
+	}
+
+	renderSetContentType(ctx, MimeTextCSVCharsetUtf8)
+	w := csv.NewWriter(ctx)
+	err := w.Write(names)
+	records := make([]string, len(names))
+	for i := 0; i < iValue.Len() && err == nil; i++ {
+		v := iValue.Index(i)
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			continue
+		}
+		for j, val := range getRow(v) {
+			records[j], err = getRenderCSVString(val)
+			if err != nil {
+				return err
+			}
+		}
+		err = w.Write(records)
+	}
+	if err != nil {
+		return err
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func getRenderCSVName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	for _, tag := range DefaultHandlerDataRenderCSVTags {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func getRenderCSVString(val any) (string, error) {
+	if val == nil {
+		return "", nil
+	}
+	switch val.(type) {
+	case fmt.Stringer, []byte:
+		return GetStringByAny(val), nil
+	}
+	switch reflect.Indirect(reflect.ValueOf(val)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		body, err := DefaultJSONMarshal(val)
+		return string(body), err
+	case reflect.Invalid:
+		return "", nil
+	}
+	return GetStringByAny(val), nil
 }
 
 // The HandlerDataRenderProtobuf function uses the built-in [NewProtobufEncoder]
@@ -271,6 +727,30 @@ func HandlerDataRenderProtobuf(ctx Context, data any) error {
 	return NewProtobufEncoder(ctx).Encode(data)
 }
 
+// The HandlerDataRenderGob function uses [gob.NewEncoder] to Render data.
+func HandlerDataRenderGob(ctx Context, data any) error {
+	renderSetContentType(ctx, MimeApplicationGob)
+	return gob.NewEncoder(ctx).Encode(data)
+}
+
+// The HandlerDataRenderMsgpack function uses [DefaultMsgpackMarshal] to
+// Render data.
+//
+// If [DefaultMsgpackMarshal] is nil, return an error and
+// [NewHandlerDataRenders] will use the next Render.
+func HandlerDataRenderMsgpack(ctx Context, data any) error {
+	if DefaultMsgpackMarshal == nil {
+		return ErrHandlerDataMsgpackNotSet
+	}
+	body, err := DefaultMsgpackMarshal(data)
+	if err != nil {
+		return err
+	}
+	renderSetContentType(ctx, MimeApplicationMsgpack)
+	_, err = ctx.Write(body)
+	return err
+}
+
 // The HandlerDataRenderHTML function creates Render using [template.Template].
 //
 // patterns will load templates from both [template.ParseFS] and
@@ -285,6 +765,12 @@ func HandlerDataRenderProtobuf(ctx Context, data any) error {
 // When returning an HTML response,
 // append [DefaultHandlerDataRenderTemplateHeaders].
 //
+// The template name uses the [ParamTemplate] param,
+// which can be set by the route param 'template=index.html' or
+// [Context.SetParam].
+// If the template execution fails, the error is logged and
+// [StatusInternalServerError] is written without partial response.
+//
 // If go run is started, workdir may be in a temp directory and the file cannot
 // be read.
 func NewHandlerDataRenderTemplates(temp *template.Template,
@@ -404,12 +890,19 @@ func renderTemplatesData(ctx Context, data any,
 		return fmt.Errorf(ErrHandlerDataRenderTemplateNotFound, name)
 	}
 
+	// execute to buffer, avoid writing partial response on error.
+	buf := &bytes.Buffer{}
+	err := t.Execute(buf, data)
+	if err != nil {
+		return renderTemplatesError(ctx, err)
+	}
+
 	hw := ctx.Response().Header()
 	for k, v := range hr {
 		if hw.Values(k) == nil {
 			hw[k] = v
 		}
 	}
-	_ = t.Execute(ctx, data)
-	return nil
+	_, err = ctx.Write(buf.Bytes())
+	return err
 }
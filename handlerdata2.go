@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -48,6 +50,14 @@ func NewHandlerDataValidate() HandlerDataFunc {
 // Get [FuncCreator] from [context.Context] to create a validation function.
 //
 // Allowed types are struct []struct []*struct []interface.
+//
+// All field failures are collected and returned as [ValidationErrors],
+// each field only reports the first failed rule.
+//
+// If the field is zero, the rules after 'omitempty' are skipped,
+// the tag suffix ',omitempty' skips all rules.
+//
+//	Email string `valid:"omitempty,mail"`
 func NewHandlerDataValidateStruct(c context.Context) HandlerDataFunc {
 	vf := &validateStruct{FuncCreator: NewFuncCreatorWithContext(c)}
 	return func(_ Context, data any) error {
@@ -62,31 +72,73 @@ type validateStruct struct {
 
 type validateStructValue struct {
 	Index  int
+	Name   string
 	Omit   bool
 	Func   FuncRunner
 	Format string
 }
 
+// ValidationErrors defines the field errors of
+// [NewHandlerDataValidateStruct], key is the field name and value is
+// the error message.
+//
+// The field name of slice element is prefixed with the index, e.g. 0.Name.
+type ValidationErrors map[string]string
+
+// The Error method implements the error interface,
+// and returns the messages sorted by field name.
+func (errs ValidationErrors) Error() string {
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	msgs := make([]string, len(keys))
+	for i, key := range keys {
+		msgs[i] = errs[key]
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// The Status method returns [StatusBadRequest].
+func (errs ValidationErrors) Status() int {
+	return StatusBadRequest
+}
+
+// The Message method returns the field errors used by Render.
+func (errs ValidationErrors) Message() any {
+	return map[string]string(errs)
+}
+
 func (vf *validateStruct) validate(v reflect.Value) error {
+	errs := make(ValidationErrors)
 	switch v.Kind() {
 	case reflect.Struct:
-		return vf.validateStructs(v)
+		err := vf.validateStructs(v, "", errs)
+		if err != nil {
+			return err
+		}
 	case reflect.Slice, reflect.Array:
 		// []struct []*struct []any
 		for i := 0; i < v.Len(); i++ {
 			field := reflect.Indirect(v.Index(i))
 			if field.Kind() == reflect.Struct {
-				err := vf.validateStructs(field)
+				err := vf.validateStructs(field, strconv.Itoa(i)+".", errs)
 				if err != nil {
 					return err
 				}
 			}
 		}
 	}
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-func (vf *validateStruct) validateStructs(v reflect.Value) error {
+func (vf *validateStruct) validateStructs(v reflect.Value, prefix string,
+	errs ValidationErrors,
+) error {
 	fields, err := vf.parseFields(v.Type())
 	if err != nil {
 		return err
@@ -94,12 +146,16 @@ func (vf *validateStruct) validateStructs(v reflect.Value) error {
 
 	// match rules
 	for _, i := range fields {
+		name := prefix + i.Name
+		if _, ok := errs[name]; ok {
+			continue
+		}
 		field := v.Field(i.Index)
 		if i.Omit && field.IsZero() {
 			continue
 		}
 		if !i.Func.RunPtr(field) {
-			return fmt.Errorf(i.Format, field.Interface())
+			errs[name] = fmt.Sprintf(i.Format, field.Interface())
 		}
 	}
 	return nil
@@ -143,6 +199,10 @@ func (vf *validateStruct) parseFields(iType reflect.Type) (
 		}
 
 		for _, tag := range splitValidateTag(tags) {
+			if tag == "omitempty" {
+				omit = true
+				continue
+			}
 			kind := NewFuncCreateKindWithType(t.Type)
 			fn, err := vf.FuncCreator.CreateFunc(kind, tag)
 			if err != nil {
@@ -153,7 +213,7 @@ func (vf *validateStruct) parseFields(iType reflect.Type) (
 			}
 
 			val := validateStructValue{
-				Index: i, Omit: omit, Func: FuncRunner{kind, fn},
+				Index: i, Name: t.Name, Omit: omit, Func: FuncRunner{kind, fn},
 				Format: fmt.Sprintf(ErrHandlerDataValidateCheckFormat,
 					iType.PkgPath(), iType.Name(), t.Name, tag),
 			}
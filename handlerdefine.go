@@ -12,6 +12,48 @@ func getCallerName(i any) string {
 	return runtime.FuncForPC(reflect.ValueOf(i).Pointer()).Name()
 }
 
+// The isRenderable function checks whether the handler data needs Render.
+//
+// If the response has been written or the request has been canceled,
+// skip Render and the canceled request outputs the [LoggerDebug] log.
+func isRenderable(ctx Context, name string) bool {
+	if ctx.Response().Size() != 0 {
+		return false
+	}
+	err := ctx.Request().Context().Err()
+	if err != nil {
+		ctx.WithField(ParamCaller, name).Debug("skip render:", err)
+		return false
+	}
+	return true
+}
+
+// The renderHandlerData function renders the returned data of the handler.
+//
+// If [DefaultHandlerRenderNoContent] is enabled and the status is not set,
+// nil data writes [StatusNoContent] with empty body instead of Render null.
+func renderHandlerData(ctx Context, data any) error {
+	if DefaultHandlerRenderNoContent && ctx.Status() == StatusOK &&
+		isNilData(data) {
+		ctx.WriteHeader(StatusNoContent)
+		return nil
+	}
+	return ctx.Render(data)
+}
+
+func isNilData(data any) bool {
+	if data == nil {
+		return true
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
 // NewHandlerFunc function converts func().
 func NewHandlerFunc(fn func()) HandlerFunc {
 	return func(Context) {
@@ -24,8 +66,8 @@ func NewHandlerFuncAny(fn func() any) HandlerFunc {
 	name := getCallerName(fn)
 	return func(ctx Context) {
 		data := fn()
-		if ctx.Response().Size() == 0 {
-			err := ctx.Render(data)
+		if isRenderable(ctx, name) {
+			err := renderHandlerData(ctx, data)
 			if err != nil {
 				ctx.WithField(ParamCaller, name).Fatal(err)
 			}
@@ -49,8 +91,8 @@ func NewHandlerFuncAnyError(fn func() (any, error)) HandlerFunc {
 	name := getCallerName(fn)
 	return func(ctx Context) {
 		data, err := fn()
-		if err == nil && ctx.Response().Size() == 0 {
-			err = ctx.Render(data)
+		if err == nil && isRenderable(ctx, name) {
+			err = renderHandlerData(ctx, data)
 		}
 		if err != nil {
 			ctx.WithField(ParamCaller, name).Fatal(err)
@@ -63,8 +105,8 @@ func NewHandlerFuncContextAny(fn func(Context) any) HandlerFunc {
 	name := getCallerName(fn)
 	return func(ctx Context) {
 		data := fn(ctx)
-		if ctx.Response().Size() == 0 {
-			err := ctx.Render(data)
+		if isRenderable(ctx, name) {
+			err := renderHandlerData(ctx, data)
 			if err != nil {
 				ctx.WithField(ParamCaller, name).Fatal(err)
 			}
@@ -83,13 +125,41 @@ func NewHandlerFuncContextError(fn func(Context) error) HandlerFunc {
 	}
 }
 
+// NewHandlerFuncContextBytes function converts func(Context) []byte,
+// writes the returned data directly without Render.
+func NewHandlerFuncContextBytes(fn func(Context) []byte) HandlerFunc {
+	name := getCallerName(fn)
+	return func(ctx Context) {
+		_, err := ctx.Write(fn(ctx))
+		if err != nil {
+			ctx.WithField(ParamCaller, name).Fatal(err)
+		}
+	}
+}
+
+// NewHandlerFuncContextStringError function converts
+// func(Context) (string, error), writes the returned string directly
+// without Render and handles error.
+func NewHandlerFuncContextStringError(fn func(Context) (string, error)) HandlerFunc {
+	name := getCallerName(fn)
+	return func(ctx Context) {
+		data, err := fn(ctx)
+		if err == nil {
+			_, err = ctx.WriteString(data)
+		}
+		if err != nil {
+			ctx.WithField(ParamCaller, name).Fatal(err)
+		}
+	}
+}
+
 // NewHandlerFuncContextAnyError function converts func(Context) (any, error), handles data Render and error.
 func NewHandlerFuncContextAnyError(fn func(Context) (any, error)) HandlerFunc {
 	name := getCallerName(fn)
 	return func(ctx Context) {
 		data, err := fn(ctx)
-		if err == nil && ctx.Response().Size() == 0 {
-			err = ctx.Render(data)
+		if err == nil && isRenderable(ctx, name) {
+			err = renderHandlerData(ctx, data)
 		}
 		if err != nil {
 			ctx.WithField(ParamCaller, name).Fatal(err)
@@ -139,8 +209,8 @@ func NewHandlerFuncContextTypeAny[T any](fn func(Context, T) any) HandlerFunc {
 		}
 
 		data := fn(ctx, *req)
-		if ctx.Response().Size() == 0 {
-			err := ctx.Render(data)
+		if isRenderable(ctx, name) {
+			err := renderHandlerData(ctx, data)
 			if err != nil {
 				ctx.WithField(ParamCaller, name).Fatal(err)
 			}
@@ -159,8 +229,8 @@ func NewHandlerFuncContextTypeAnyError[T any](fn func(Context, T) (any, error))
 		}
 
 		data, err := fn(ctx, *req)
-		if err == nil && ctx.Response().Size() == 0 {
-			err = ctx.Render(data)
+		if err == nil && isRenderable(ctx, name) {
+			err = renderHandlerData(ctx, data)
 		}
 		if err != nil {
 			ctx.WithField(ParamCaller, name).Fatal(err)
@@ -226,9 +296,11 @@ func NewHandlerAnyContextTypeAnyError(fn any) HandlerFunc {
 		}
 
 		// render the returned data.
-		err = ctx.Render(vals[0].Interface())
-		if err != nil {
-			ctx.Fatal(err)
+		if isRenderable(ctx, name) {
+			err = renderHandlerData(ctx, vals[0].Interface())
+			if err != nil {
+				ctx.Fatal(err)
+			}
 		}
 	}
 }
@@ -249,8 +321,8 @@ func NewHandlerFuncContextMapAnyError(fn func(Context, map[string]any) (any, err
 			return
 		}
 		resp, err := fn(ctx, req)
-		if err == nil && ctx.Response().Size() == 0 {
-			err = ctx.Render(resp)
+		if err == nil && isRenderable(ctx, name) {
+			err = renderHandlerData(ctx, resp)
 		}
 		if err != nil {
 			ctx.WithField(ParamCaller, name).Fatal(err)
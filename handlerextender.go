@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 )
 
 // HandlerExtender defines the extension management that converts any func into
@@ -42,13 +43,17 @@ type MetadataHandlerExtender struct {
 var (
 	// The contextFuncName key type must be of HandlerFunc type,
 	// which stores the correct name of the function.
-	contextFuncName  = make(map[uintptr]string)   // final Name
-	contextSaveName  = make(map[uintptr]string)   // function name
-	contextAliasName = make(map[uintptr][]string) // object Name
+	contextFuncName  = make(map[funcPointer]string) // final Name
+	contextSaveName  = make(map[funcPointer]string) // function name
+	contextAliasName = make(map[uintptr][]string)   // object Name
 )
 
 // The NewHandlerExtender function creates [NewHandlerExtenderBase]
 // and loads the extended functions in [DefaultHandlerExtenderFuncs].
+//
+// Each call returns a new [HandlerExtender] with the built-in extenders,
+// registering to it does not modify [DefaultHandlerExtender];
+// use [NewHandlerExtenderBase] to create an empty [HandlerExtender].
 func NewHandlerExtender() HandlerExtender {
 	he := NewHandlerExtenderBase()
 	for _, fn := range DefaultHandlerExtenderFuncs {
@@ -79,6 +84,11 @@ type handlerExtenderBase struct {
 // The NewHandlerExtenderBase method creates a basic [HandlerExtender].
 //
 // Implement registration and creation of [HandlerFunc].
+//
+// The returned [HandlerExtender] is empty and only supports [HandlerFunc]
+// and func([Context]), other types such as [http.Handler] are not converted
+// until the extender like [NewHandlerHTTPHandler] is registered;
+// use [NewHandlerExtender] to load the built-in extenders.
 func NewHandlerExtenderBase() HandlerExtender {
 	return &handlerExtenderBase{
 		allowKinds: mapClone(DefaultHandlerExtenderAllowKind),
@@ -89,8 +99,10 @@ func NewHandlerExtenderBase() HandlerExtender {
 func (he *handlerExtenderBase) RegisterExtender(_ string, fn any) error {
 	iType := reflect.TypeOf(fn)
 	// fn value must be a func type
-	if iType.Kind() != reflect.Func {
-		return ErrHandlerExtenderParamNotFunc
+	if iType == nil || iType.Kind() != reflect.Func {
+		return fmt.Errorf(ErrHandlerExtenderParamType,
+			ErrHandlerExtenderParamNotFunc, fn,
+		)
 	}
 
 	// Check that the fn type must be func(Type) HandlerFunc or
@@ -316,6 +328,7 @@ func (he *handlerExtenderWrap) Metadata() any {
 
 // handlerExtenderTree defines [HandlerExtender] based on path matching.
 type handlerExtenderTree struct {
+	sync.RWMutex
 	root handlerExtenderNode
 }
 type handlerExtenderNode = radixNode[*handlerExtenderData, handlerExtenderData]
@@ -330,6 +343,9 @@ type handlerExtenderData struct {
 // Group the extension functions by registering the path
 // and create a [HandlerFunc] that selects the extension function with the
 // longest path.
+//
+// RegisterExtender can be called concurrently with CreateHandlers,
+// allowing path-scoped extenders to be added at runtime.
 func NewHandlerExtenderTree() HandlerExtender {
 	return &handlerExtenderTree{}
 }
@@ -342,10 +358,14 @@ func (data *handlerExtenderData) Insert(vals ...any) error {
 }
 
 func (he *handlerExtenderTree) RegisterExtender(path string, fn any) error {
+	he.Lock()
+	defer he.Unlock()
 	return he.root.insert(path, path, fn)
 }
 
 func (he *handlerExtenderTree) CreateHandlers(path string, data any) []HandlerFunc {
+	he.RLock()
+	defer he.RUnlock()
 	vals := he.root.lookPath(path)
 	for i := len(vals) - 1; i >= 0; i-- {
 		h := vals[i].CreateHandlers(path, data)
@@ -367,6 +387,8 @@ func (he *handlerExtenderTree) Metadata() any {
 // The List method recursively adds path prefixes
 // and returns extension function names.
 func (he *handlerExtenderTree) List() []string {
+	he.RLock()
+	defer he.RUnlock()
 	return handlerExtenderList(&he.root, "")
 }
 
@@ -110,25 +110,33 @@ type LoggerHandler interface {
 // If HookFatal is true, use [NewLoggerHookFatal].
 //
 // If HookMeta is true and AsyncSize is 0, use [NewLoggerHookMeta].
+//
+// If HookRepeat is greater than 0, use [NewLoggerHookRepeat].
+//
+// If Reload is true, the [Logger] implements the
+// Reload(*LoggerConfig) error method to replace the level and handlers
+// at runtime.
 type LoggerConfig struct {
 	// Custom LoggerHandler
 	Handlers     []LoggerHandler `alias:"handlers" json:"-" yaml:"-"`
-	Level        LoggerLevel     `alias:"level" json:"level" yaml:"level"`
-	AsyncSize    int             `alias:"asyncSize" json:"asyncSize" yaml:"asyncSize"`
-	AsyncTimeout time.Duration   `alias:"asyncTimeout" json:"asyncTimeout" yaml:"asyncTimeout"`
-	Caller       bool            `alias:"caller" json:"caller" yaml:"caller"`
-	Stdout       bool            `alias:"stdout" json:"stdout" yaml:"stdout"`
-	StdColor     bool            `alias:"stdColor" json:"stdColor" yaml:"stdColor"`
-	Formatter    string          `alias:"formater" json:"formater" yaml:"formater"`
-	TimeFormat   string          `alias:"timeFormat" json:"timeFormat" yaml:"timeFormat"`
+	Level        LoggerLevel     `alias:"level" json:"level" yaml:"level" description:"output log level"`
+	AsyncSize    int             `alias:"asyncSize" json:"asyncSize" yaml:"asyncSize" description:"async writer buffer size"`
+	AsyncTimeout time.Duration   `alias:"asyncTimeout" json:"asyncTimeout" yaml:"asyncTimeout" description:"async writer flush timeout"`
+	Caller       bool            `alias:"caller" json:"caller" yaml:"caller" description:"output caller file and line"`
+	Stdout       bool            `alias:"stdout" json:"stdout" yaml:"stdout" description:"output to stdout"`
+	StdColor     bool            `alias:"stdColor" json:"stdColor" yaml:"stdColor" description:"output color level to stdout"`
+	Formatter    string          `alias:"formater" json:"formater" yaml:"formater" description:"log formatter json or text"`
+	TimeFormat   string          `alias:"timeFormat" json:"timeFormat" yaml:"timeFormat" description:"time formatting layout"`
 	HookFilter   [][]string      `alias:"hookFilter" json:"hookFilter" yaml:"hookFilter"`
-	HookFatal    bool            `alias:"hookFatal" json:"hookFatal" yaml:"hookFatal"`
-	HookMeta     bool            `alias:"hookMeta" json:"hookMeta" yaml:"hookMeta"`
-	Path         string          `alias:"path" json:"path" yaml:"path"`
-	Link         string          `alias:"link" json:"link" yaml:"link"`
-	MaxSize      uint64          `alias:"maxSize" json:"maxSize" yaml:"maxSize"`
-	MaxAge       int             `alias:"maxAge" json:"maxAge" yaml:"maxAge"`
-	MaxCount     int             `alias:"maxCount" json:"maxCount" yaml:"maxCount"`
+	HookFatal    bool            `alias:"hookFatal" json:"hookFatal" yaml:"hookFatal" description:"exit the program when logging fatal"`
+	HookMeta     bool            `alias:"hookMeta" json:"hookMeta" yaml:"hookMeta" description:"record log count and size"`
+	HookRepeat   time.Duration   `alias:"hookRepeat" json:"hookRepeat" yaml:"hookRepeat" description:"collapse repeated error logs flush interval"`
+	Path         string          `alias:"path" json:"path" yaml:"path" description:"log file path"`
+	Link         string          `alias:"link" json:"link" yaml:"link" description:"log file soft link path"`
+	MaxSize      uint64          `alias:"maxSize" json:"maxSize" yaml:"maxSize" description:"rotate file max size"`
+	MaxAge       int             `alias:"maxAge" json:"maxAge" yaml:"maxAge" description:"rotate file max age days"`
+	MaxCount     int             `alias:"maxCount" json:"maxCount" yaml:"maxCount" description:"rotate file max count"`
+	Reload       bool            `alias:"reload" json:"reload" yaml:"reload" description:"allow reloading config at runtime"`
 }
 
 type MetadataLogger struct {
@@ -150,6 +158,9 @@ func NewLogger(config *LoggerConfig) Logger {
 	}
 
 	handlers := config.getHandlers()
+	if config.Reload {
+		handlers = []LoggerHandler{&loggerHandlerReload{Handlers: handlers}}
+	}
 	size := DefaultLoggerEntryFieldsLength
 	buff := DefaultLoggerEntryBufferLength
 	pool := &sync.Pool{}
@@ -220,6 +231,9 @@ func (c *LoggerConfig) getHooks() []LoggerHandler {
 	if c.HookFatal {
 		hooks = append(hooks, NewLoggerHookFatal(nil))
 	}
+	if c.HookRepeat > 0 {
+		hooks = append(hooks, NewLoggerHookRepeat(c.HookRepeat))
+	}
 	return hooks
 }
 
@@ -329,6 +343,38 @@ func (log *loggerStd) Metadata() any {
 	return nil
 }
 
+// The Reload method uses config to create new handlers and replace the
+// handlers and level, the [LoggerConfig].Reload of [NewLogger] must be true.
+//
+// The entries being handled are written to the old handlers,
+// and then the old handlers are unmounted and closed.
+func (log *loggerStd) Reload(config *LoggerConfig) (err error) {
+	var reload *loggerHandlerReload
+	for i := range log.Handlers {
+		h, ok := log.Handlers[i].(*loggerHandlerReload)
+		if ok {
+			reload = h
+		}
+	}
+	if reload == nil {
+		return ErrLoggerReloadDisabled
+	}
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			e, ok := r.(error)
+			if !ok {
+				e = fmt.Errorf("%v", r)
+			}
+			err = e
+		}
+	}()
+	reload.Reload(config.getHandlers())
+	log.SetLevel(config.Level)
+	return nil
+}
+
 func (log *loggerStd) GetLevel() LoggerLevel {
 	return log.Level
 }
@@ -558,7 +604,14 @@ func (l *LoggerLevel) UnmarshalText(text []byte) error {
 
 var works = [...]string{"/pkg/mod/", "/src/"}
 
+// callerFuncFiles caches the func name and file line of the caller pc.
+var callerFuncFiles sync.Map
+
 func trimFileName(name string) string {
+	prefix := DefaultLoggerCallerTrimPrefix
+	if prefix != "" && strings.HasPrefix(name, prefix) {
+		return name[len(prefix):]
+	}
 	for _, w := range works {
 		pos := strings.Index(name, w)
 		if pos != -1 {
@@ -580,21 +633,37 @@ func trimFuncName(name string) string {
 // function name.
 //
 // func name does not retain the package path, file name ignores the
-// $GOPATH path.
+// $GOPATH path or [DefaultLoggerCallerTrimPrefix].
+//
+// If [DefaultLoggerCallerCache] is enabled, the resolved result is cached
+// by the caller pc, repeated calls from the same line reuse it.
 func GetCallerFuncFile(depth int) (string, string) {
 	var pcs [1]uintptr
 	runtime.Callers(depth+1, pcs[:])
+	if DefaultLoggerCallerCache {
+		val, ok := callerFuncFiles.Load(pcs[0])
+		if ok {
+			caller := val.(*[2]string)
+			return caller[0], trimFileName(caller[1])
+		}
+	}
+
 	fs := runtime.CallersFrames(pcs[:])
 	f, _ := fs.Next()
-
-	return trimFuncName(f.Function),
-		trimFileName(f.File + ":" + strconv.Itoa(f.Line))
+	caller := &[2]string{
+		trimFuncName(f.Function),
+		f.File + ":" + strconv.Itoa(f.Line),
+	}
+	if DefaultLoggerCallerCache {
+		callerFuncFiles.Store(pcs[0], caller)
+	}
+	return caller[0], trimFileName(caller[1])
 }
 
 // The GetCallerStacks function returns the caller stack information.
 //
 // func name does not retain the package path, file name ignores the
-// $GOPATH path.
+// $GOPATH path or [DefaultLoggerCallerTrimPrefix].
 func GetCallerStacks(depth int) []string {
 	pc := make([]uintptr, DefaultLoggerDepthMaxStack)
 	n := runtime.Callers(depth, pc)
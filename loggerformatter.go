@@ -1,8 +1,10 @@
 package eudore
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -306,7 +308,7 @@ func parseJSONStructFields(iType reflect.Type) []encodeJSONField {
 	var fields []encodeJSONField
 	for i := 0; i < iType.NumField(); i++ {
 		t := iType.Field(i)
-		name, omit := cutOmit(t.Tag.Get("json"))
+		name, omit := cutOmit(getLoggerStructTag(t.Tag))
 		if name == "-" || t.Name[0] < 'A' || t.Name[0] > 'Z' {
 			continue
 		}
@@ -332,6 +334,18 @@ func parseJSONStructFields(iType reflect.Type) []encodeJSONField {
 	return fields
 }
 
+// The getLoggerStructTag function returns the first existing tag in
+// [DefaultLoggerFormatterTags].
+func getLoggerStructTag(tag reflect.StructTag) string {
+	for _, key := range DefaultLoggerFormatterTags {
+		val, ok := tag.Lookup(key)
+		if ok {
+			return val
+		}
+	}
+	return ""
+}
+
 type ptrEnocder struct {
 	Elem typeEncoder
 }
@@ -454,17 +468,34 @@ func (e *structEncoder) encodeFields(en *loggerEncoder, v reflect.Value) {
 			continue
 		}
 
-		if f.Omit && v.IsZero() {
+		if f.Omit && isEmptyValue(v) {
 			continue
 		}
+		pos := len(en.data)
 		en.WriteBytes('"')
 		en.WriteString(f.Name)
 		en.WriteBytes('"', ':')
 		f.Encoder(en, v)
+		if f.Omit && DefaultLoggerFormatterOmitEmptyStruct &&
+			v.Kind() == reflect.Struct && en.data[len(en.data)-1] == '}' &&
+			en.data[len(en.data)-2] == '{' {
+			en.data = en.data[:pos]
+			continue
+		}
 		en.WriteBytes(',')
 	}
 }
 
+// The isEmptyValue function checks omitempty, the empty map and slice
+// are omitted like encoding/json, other types use IsZero.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
 func valueEncoder(en *loggerEncoder, v reflect.Value) {
 	// 写入类型
 	switch v.Kind() {
@@ -476,13 +507,20 @@ func valueEncoder(en *loggerEncoder, v reflect.Value) {
 		reflect.Uint32, reflect.Uint64, reflect.Uintptr:
 		en.data = strconv.AppendUint(en.data, v.Uint(), 10)
 	case reflect.Float32, reflect.Float64:
-		en.data = strconv.AppendFloat(en.data, v.Float(), 'f', -1, 64)
+		en.data = strconv.AppendFloat(en.data, v.Float(), 'f',
+			DefaultLoggerFormatterFloatPrecision, v.Type().Bits(),
+		)
 	case reflect.Complex64, reflect.Complex128:
 		val := v.Complex()
+		bits := v.Type().Bits() / 2
 		en.WriteBytes('"')
-		en.data = strconv.AppendFloat(en.data, real(val), 'f', -1, 64)
+		en.data = strconv.AppendFloat(en.data, real(val), 'f',
+			DefaultLoggerFormatterFloatPrecision, bits,
+		)
 		en.WriteBytes('+')
-		en.data = strconv.AppendFloat(en.data, imag(val), 'f', -1, 64)
+		en.data = strconv.AppendFloat(en.data, imag(val), 'f',
+			DefaultLoggerFormatterFloatPrecision, bits,
+		)
 		en.WriteBytes('i', '"')
 	case reflect.String:
 		en.WriteBytes('"')
@@ -504,6 +542,7 @@ func errorEncoder(en *loggerEncoder, v reflect.Value) {
 		en.WriteString("null")
 		return
 	}
+	defer recoverEncoder(en, len(en.data))
 	en.WriteBytes('"')
 	en.WriteString(v.Interface().(error).Error())
 	en.WriteBytes('"')
@@ -514,6 +553,7 @@ func fmtStringerEncoder(en *loggerEncoder, v reflect.Value) {
 		en.WriteString("null")
 		return
 	}
+	defer recoverEncoder(en, len(en.data))
 	en.WriteBytes('"')
 	en.WriteString(v.Interface().(fmt.Stringer).String())
 	en.WriteBytes('"')
@@ -524,10 +564,19 @@ func jsonMarshalerEncoder(en *loggerEncoder, v reflect.Value) {
 		en.WriteString("null")
 		return
 	}
-	body, err := v.Interface().(json.Marshaler).MarshalJSON()
+	defer recoverEncoder(en, len(en.data))
+	body, err := DefaultJSONMarshal(v.Interface())
 	if err == nil {
-		en.WriteBytes(body...)
-	} else {
+		// Compact validates body and truncates buffer when body is invalid.
+		buf := bytes.NewBuffer(en.data)
+		err = json.Compact(buf, body)
+		en.data = buf.Bytes()
+	}
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		err = fmt.Errorf(ErrLoggerMarshalJSONInvalid, v.Type().String(), err)
+	}
+	if err != nil {
 		en.WriteBytes('"')
 		en.formatString(err.Error())
 		en.WriteBytes('"')
@@ -539,6 +588,7 @@ func textMarshalerEncoder(en *loggerEncoder, v reflect.Value) {
 		en.WriteString("null")
 		return
 	}
+	defer recoverEncoder(en, len(en.data))
 	body, err := v.Interface().(encoding.TextMarshaler).MarshalText()
 	en.WriteBytes('"')
 	if err == nil {
@@ -549,6 +599,17 @@ func textMarshalerEncoder(en *loggerEncoder, v reflect.Value) {
 	en.WriteBytes('"')
 }
 
+// The recoverEncoder function recovers the panic of the user method,
+// truncates the partial output and writes a placeholder string.
+func recoverEncoder(en *loggerEncoder, size int) {
+	if r := recover(); r != nil {
+		en.data = en.data[:size]
+		en.WriteBytes('"')
+		en.formatString(fmt.Sprintf(ErrLoggerEncoderPanic, r))
+		en.WriteBytes('"')
+	}
+}
+
 func (en *loggerEncoder) formatVia(v reflect.Value) bool {
 	if v.IsNil() {
 		en.WriteString("null")
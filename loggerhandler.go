@@ -3,6 +3,10 @@ package eudore
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -71,6 +75,82 @@ func (h *loggerHandlerInit) Unmount(ctx context.Context) {
 	h.Entrys = nil
 }
 
+// loggerHandlerReload forwards [LoggerEntry] to the handlers that can be
+// replaced at runtime, used by [LoggerConfig].Reload.
+type loggerHandlerReload struct {
+	sync.RWMutex
+	Handlers []LoggerHandler
+	Context  context.Context
+}
+
+func (h *loggerHandlerReload) HandlerPriority() int {
+	return 0
+}
+
+func (h *loggerHandlerReload) HandlerEntry(entry *LoggerEntry) {
+	h.RLock()
+	defer h.RUnlock()
+	for _, handler := range h.Handlers {
+		if entry.Level < LoggerDiscard {
+			handler.HandlerEntry(entry)
+		}
+	}
+}
+
+func (h *loggerHandlerReload) Mount(ctx context.Context) {
+	h.Lock()
+	defer h.Unlock()
+	h.Context = ctx
+	for i := range h.Handlers {
+		anyMount(ctx, h.Handlers[i])
+	}
+}
+
+func (h *loggerHandlerReload) Unmount(ctx context.Context) {
+	h.Lock()
+	defer h.Unlock()
+	for i := len(h.Handlers) - 1; i > -1; i-- {
+		anyUnmount(ctx, h.Handlers[i])
+	}
+}
+
+func (h *loggerHandlerReload) Metadata() any {
+	h.RLock()
+	defer h.RUnlock()
+	for i := range h.Handlers {
+		meta := anyMetadata(h.Handlers[i])
+		if meta != nil {
+			return meta
+		}
+	}
+	return nil
+}
+
+// The Reload method replaces the handlers after the in-flight entries are
+// handled, then unmounts and closes the old handlers.
+func (h *loggerHandlerReload) Reload(handlers []LoggerHandler) {
+	h.Lock()
+	olds := h.Handlers
+	h.Handlers = handlers
+	ctx := h.Context
+	if ctx != nil {
+		for i := range handlers {
+			anyMount(ctx, handlers[i])
+		}
+	} else {
+		ctx = context.Background()
+	}
+	h.Unlock()
+
+	for i := len(olds) - 1; i > -1; i-- {
+		anyUnmount(ctx, olds[i])
+		closer, ok := olds[i].(io.Closer)
+		if ok {
+			_ = closer.Close()
+		}
+	}
+}
+
 type loggerHookMeta struct {
 	Size  uint64
 	Count [6]uint64
@@ -254,6 +334,104 @@ func (h *loggerHookFatal) HandlerEntry(entry *LoggerEntry) {
 	}
 }
 
+type loggerHookRepeat struct {
+	sync.Mutex
+	Context  context.Context
+	Interval time.Duration
+	Key      string
+	Count    int
+	Message  string
+	Keys     []string
+	Vals     []any
+	timer    *time.Timer
+}
+
+// The NewLoggerHookRepeat function creates [LoggerHandler] to implement
+// collapse identical consecutive [LoggerError] and [LoggerFatal] logs.
+//
+// Logs with the same Message and stack field are discarded and counted,
+// when a different error log arrives or after interval, output one
+// [LoggerError] log with the field repeated:N.
+// Logs lower than [LoggerError] do not interrupt consecutive.
+//
+// In Mount, save the [context.Context] and use [NewLoggerWithContext] to
+// output the repeated log, if not mounted the count is discarded.
+func NewLoggerHookRepeat(interval time.Duration) LoggerHandler {
+	return &loggerHookRepeat{Interval: interval}
+}
+
+func (h *loggerHookRepeat) Mount(ctx context.Context) {
+	h.Lock()
+	h.Context = ctx
+	h.Unlock()
+}
+
+func (h *loggerHookRepeat) Unmount(context.Context) {
+	h.flush()
+}
+
+func (h *loggerHookRepeat) HandlerPriority() int {
+	return DefaultLoggerPriorityHookRepeat
+}
+
+func (h *loggerHookRepeat) HandlerEntry(entry *LoggerEntry) {
+	if entry.Level < LoggerError || entry.Level > LoggerFatal {
+		return
+	}
+	var stack any
+	for i, key := range entry.Keys {
+		switch key {
+		case "repeated":
+			return
+		case "stack":
+			stack = entry.Vals[i]
+		}
+	}
+
+	key := fmt.Sprint(entry.Message, stack)
+	h.Lock()
+	if key == h.Key {
+		h.Count++
+		entry.Level = LoggerDiscard
+		if h.timer == nil && h.Interval > 0 {
+			h.timer = time.AfterFunc(h.Interval, h.flush)
+		}
+		h.Unlock()
+		return
+	}
+	h.Unlock()
+
+	h.flush()
+	h.Lock()
+	h.Key, h.Message = key, entry.Message
+	h.Keys = append(h.Keys[:0], entry.Keys...)
+	h.Vals = append(h.Vals[:0], entry.Vals...)
+	h.Unlock()
+}
+
+// The flush method outputs the repeated log and resets the count.
+func (h *loggerHookRepeat) flush() {
+	h.Lock()
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	count, ctx := h.Count, h.Context
+	if count == 0 || ctx == nil {
+		h.Count = 0
+		h.Unlock()
+		return
+	}
+	h.Count = 0
+	log := NewLoggerWithContext(ctx).
+		WithFields(append([]string{}, h.Keys...), append([]any{}, h.Vals...)).
+		WithField(ParamDepth, DefaultLoggerDepthKindDisable).
+		WithField("repeated", count)
+	message := h.Message
+	h.Unlock()
+	log.Error(message)
+}
+
 type loggerWriterAsync struct {
 	loggerHookMeta
 	Handlers []LoggerHandler
@@ -261,6 +439,7 @@ type loggerWriterAsync struct {
 	timeout  time.Duration
 	async    chan *LoggerEntry
 	done     chan struct{}
+	exit     chan struct{}
 }
 
 // The NewLoggerWriterAsync function creates [LoggerHandler] to implement
@@ -295,16 +474,23 @@ func NewLoggerWriterAsync(handlers []LoggerHandler, size, buff int,
 }
 
 func (w *loggerWriterAsync) Mount(ctx context.Context) {
+	w.exit = make(chan struct{})
 	go func() {
+		defer close(w.exit)
 		for {
 			select {
 			case log := <-w.async:
-				for _, h := range w.Handlers {
-					h.HandlerEntry(log)
-				}
-				w.pool.Put(&log.Buffer)
+				w.handlerEntry(log)
 			case <-w.done:
-				return
+				// handle the buffered entries before exiting
+				for {
+					select {
+					case log := <-w.async:
+						w.handlerEntry(log)
+					default:
+						return
+					}
+				}
 			}
 		}
 	}()
@@ -313,11 +499,26 @@ func (w *loggerWriterAsync) Mount(ctx context.Context) {
 	}
 }
 
+// The Unmount method waits up to timeout for the buffered entries to be
+// handled, and then unmounts handlers.
 func (w *loggerWriterAsync) Unmount(ctx context.Context) {
+	close(w.done)
+	if w.exit != nil {
+		select {
+		case <-w.exit:
+		case <-time.After(w.timeout):
+		}
+	}
 	for _, h := range w.Handlers {
 		anyUnmount(ctx, h)
 	}
-	close(w.done)
+}
+
+func (w *loggerWriterAsync) handlerEntry(log *LoggerEntry) {
+	for _, h := range w.Handlers {
+		h.HandlerEntry(log)
+	}
+	w.pool.Put(&log.Buffer)
 }
 
 func (w *loggerWriterAsync) HandlerPriority() int {
@@ -422,6 +623,270 @@ func (w *loggerWriterFile) HandlerEntry(entry *LoggerEntry) {
 	w.Unlock()
 }
 
+// The Close method closes the file, used when [LoggerConfig].Reload
+// replaces the writer.
+func (w *loggerWriterFile) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	return w.File.Close()
+}
+
+type loggerWriterRing struct {
+	sync.RWMutex
+	Entries []string
+	Index   int
+	Full    bool
+}
+
+// The NewLoggerWriterRing function creates [LoggerHandler] to save the
+// most recent size logs in a fixed ring, used by the admin UI to view logs.
+//
+// The returned value implements [http.Handler] and responds to the logs in
+// order as json array, or text lines if the uri parameter format=text or
+// [HeaderAccept] is [MimeTextPlain].
+func NewLoggerWriterRing(size int) LoggerHandler {
+	if size < 1 {
+		size = 1
+	}
+	return &loggerWriterRing{Entries: make([]string, size)}
+}
+
+func (w *loggerWriterRing) HandlerPriority() int {
+	return DefaultLoggerPriorityWriterRing
+}
+
+func (w *loggerWriterRing) HandlerEntry(entry *LoggerEntry) {
+	line := string(bytes.TrimRight(entry.Buffer, "\r\n"))
+	w.Lock()
+	w.Entries[w.Index] = line
+	w.Index++
+	if w.Index == len(w.Entries) {
+		w.Index, w.Full = 0, true
+	}
+	w.Unlock()
+}
+
+// The Lines method returns the saved logs from oldest to newest.
+func (w *loggerWriterRing) Lines() []string {
+	w.RLock()
+	defer w.RUnlock()
+	if !w.Full {
+		return append([]string{}, w.Entries[:w.Index]...)
+	}
+	lines := make([]string, 0, len(w.Entries))
+	lines = append(lines, w.Entries[w.Index:]...)
+	return append(lines, w.Entries[:w.Index]...)
+}
+
+func (w *loggerWriterRing) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	lines := w.Lines()
+	h := resp.Header()
+	h.Set(HeaderCacheControl, HeaderValueNoCache)
+	if req.URL.Query().Get("format") == "text" ||
+		strings.HasPrefix(req.Header.Get(HeaderAccept), MimeTextPlain) {
+		h.Set(HeaderContentType, MimeTextPlainCharsetUtf8)
+		for _, line := range lines {
+			_, _ = io.WriteString(resp, line+"\n")
+		}
+		return
+	}
+
+	body, err := DefaultJSONMarshal(lines)
+	if err != nil {
+		http.Error(resp, err.Error(), StatusInternalServerError)
+		return
+	}
+	h.Set(HeaderContentType, MimeApplicationJSONCharsetUtf8)
+	_, _ = resp.Write(body)
+}
+
+type loggerWriterNetwork struct {
+	sync.Mutex
+	Network string
+	Address string
+	Conn    net.Conn
+	Entries [][]byte
+	Size    int
+	Discard uint64
+	Backoff time.Duration
+	Retry   time.Time
+	// flushing serializes dial and write out of the Mutex.
+	flushing sync.Mutex
+	start    sync.Once
+	stop     sync.Once
+	notify   chan struct{}
+	done     chan struct{}
+	exit     chan struct{}
+}
+
+// The NewLoggerWriterNetwork function creates [LoggerHandler] to write each
+// formatted log to the network collector, such as tcp or udp.
+//
+// HandlerEntry only appends the log to the buffer,
+// the connection and writing are done in the background goroutine,
+// so the log call will not be blocked by the unavailable collector.
+//
+// When the connection fails, reconnect after the backoff between
+// [DefaultLoggerWriterNetworkBackoffMin] and
+// [DefaultLoggerWriterNetworkBackoffMax], the most recent size logs are
+// buffered while disconnected and the oldest logs are discarded.
+//
+// The Sync method reconnects immediately and writes the buffered logs,
+// the Unmount method stops the background goroutine.
+func NewLoggerWriterNetwork(network, addr string, size int) LoggerHandler {
+	if size < 1 {
+		size = 1
+	}
+	return &loggerWriterNetwork{
+		Network: network,
+		Address: addr,
+		Size:    size,
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		exit:    make(chan struct{}),
+	}
+}
+
+func (w *loggerWriterNetwork) Unmount(context.Context) {
+	w.stop.Do(func() { close(w.done) })
+	// waits for the background goroutine to exit, if it has been started.
+	w.start.Do(func() { close(w.exit) })
+	<-w.exit
+	_ = w.Sync()
+	w.Lock()
+	if w.Conn != nil {
+		w.Conn.Close()
+		w.Conn = nil
+	}
+	w.Unlock()
+}
+
+func (w *loggerWriterNetwork) Metadata() any {
+	w.Lock()
+	defer w.Unlock()
+	return MetadataLogger{
+		Health: w.Conn != nil,
+		Name:   "eudore.loggerWriterNetwork",
+		Count:  [6]uint64{LoggerDiscard: w.Discard},
+	}
+}
+
+func (w *loggerWriterNetwork) HandlerPriority() int {
+	return DefaultLoggerPriorityWriterNetwork
+}
+
+func (w *loggerWriterNetwork) HandlerEntry(entry *LoggerEntry) {
+	w.start.Do(func() { go w.run() })
+	w.Lock()
+	w.Entries = append(w.Entries, append([]byte{}, entry.Buffer...))
+	w.trimEntries()
+	w.Unlock()
+	w.signal()
+}
+
+// The Sync method reconnects and writes the buffered logs,
+// returns the error if the logs are not all written.
+func (w *loggerWriterNetwork) Sync() error {
+	return w.flush()
+}
+
+func (w *loggerWriterNetwork) signal() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// The run method flushes the buffered logs in the background,
+// and waits for the backoff after the connection fails.
+func (w *loggerWriterNetwork) run() {
+	defer close(w.exit)
+	for {
+		select {
+		case <-w.notify:
+		case <-w.done:
+			return
+		}
+		w.Lock()
+		wait := time.Until(w.Retry)
+		w.Unlock()
+		if wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-w.done:
+				return
+			}
+		}
+		if w.flush() != nil {
+			w.signal()
+		}
+	}
+}
+
+// The flush method dials and writes without holding the Mutex,
+// the unwritten logs are put back to the buffer.
+func (w *loggerWriterNetwork) flush() error {
+	w.flushing.Lock()
+	defer w.flushing.Unlock()
+	w.Lock()
+	conn, entries := w.Conn, w.Entries
+	w.Entries = nil
+	w.Unlock()
+
+	if conn == nil {
+		var err error
+		conn, err = net.DialTimeout(w.Network, w.Address,
+			DefaultLoggerWriterNetworkTimeout,
+		)
+		if err != nil {
+			w.requeue(entries)
+			return err
+		}
+		w.Lock()
+		w.Conn, w.Backoff = conn, 0
+		w.Unlock()
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(DefaultLoggerWriterNetworkTimeout))
+	for i, entry := range entries {
+		_, err := conn.Write(entry)
+		if err != nil {
+			conn.Close()
+			w.requeue(entries[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *loggerWriterNetwork) requeue(entries [][]byte) {
+	w.Lock()
+	defer w.Unlock()
+	w.Conn = nil
+	w.Entries = append(entries, w.Entries...)
+	w.trimEntries()
+	w.backoff()
+}
+
+func (w *loggerWriterNetwork) trimEntries() {
+	if len(w.Entries) > w.Size {
+		w.Discard += uint64(len(w.Entries) - w.Size)
+		w.Entries = append(w.Entries[:0], w.Entries[len(w.Entries)-w.Size:]...)
+	}
+}
+
+func (w *loggerWriterNetwork) backoff() {
+	switch {
+	case w.Backoff < DefaultLoggerWriterNetworkBackoffMin:
+		w.Backoff = DefaultLoggerWriterNetworkBackoffMin
+	case w.Backoff*2 > DefaultLoggerWriterNetworkBackoffMax:
+		w.Backoff = DefaultLoggerWriterNetworkBackoffMax
+	default:
+		w.Backoff *= 2
+	}
+	w.Retry = time.Now().Add(w.Backoff)
+}
+
 type loggerWriterRotate struct {
 	loggerWriterFile
 	name      string
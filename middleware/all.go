@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -32,6 +34,87 @@ func NewAdminFunc() Middleware {
 	}
 }
 
+// The NewAuditFunc function creates middleware to implement
+// output audit logs of the mutating request to log.
+//
+// Only the unsafe methods POST PUT PATCH DELETE are recorded, fields:
+// user method path route realip status duration body,
+// and the log time is written by the formatter.
+//
+// The user is the first non-empty param of [eudore.ParamUserid]
+// [eudore.ParamUsername] [eudore.ParamBasicAuth].
+//
+// The json and form body is redacted using fields or
+// [DefaultBindDebugRedactFields], other bodies are truncated to
+// [DefaultAuditBodySize].
+func NewAuditFunc(log eudore.Logger, fields ...string) Middleware {
+	if len(fields) == 0 {
+		fields = DefaultBindDebugRedactFields
+	}
+	redact := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		redact[strings.ToLower(field)] = struct{}{}
+	}
+	log = log.WithField(eudore.ParamDepth, eudore.DefaultLoggerDepthKindDisable).
+		WithField("logger", true)
+	return func(ctx eudore.Context) {
+		switch ctx.Method() {
+		case eudore.MethodPost, eudore.MethodPut,
+			eudore.MethodPatch, eudore.MethodDelete:
+		default:
+			return
+		}
+
+		now := time.Now()
+		body := auditBody(ctx, redact)
+		ctx.Next()
+		user := ctx.GetParam(eudore.ParamUserid)
+		for _, key := range [...]string{eudore.ParamUsername, eudore.ParamBasicAuth} {
+			if user == "" {
+				user = ctx.GetParam(key)
+			}
+		}
+		log.WithFields(
+			[]string{"user", "method", "path", "route", "realip", "status",
+				"duration",
+			},
+			[]any{
+				user, ctx.Method(), ctx.Path(), ctx.GetParam(eudore.ParamRoute),
+				ctx.RealIP(), ctx.Status(),
+				eudore.GetStringDuration(time.Since(now) / 1000),
+			},
+		).WithField("body", body).Info("audit")
+	}
+}
+
+func auditBody(ctx eudore.Context, redact map[string]struct{}) any {
+	body, err := ctx.Body()
+	if err != nil || len(body) == 0 {
+		return nil
+	}
+	mime := ctx.GetHeader(eudore.HeaderContentType)
+	switch {
+	case strings.HasPrefix(mime, eudore.MimeApplicationJSON):
+		var val any
+		if json.Unmarshal(body, &val) == nil {
+			return redactBindData(val, redact)
+		}
+	case strings.HasPrefix(mime, eudore.MimeApplicationForm):
+		form, err := url.ParseQuery(string(body))
+		if err == nil {
+			val := make(map[string]any, len(form))
+			for k, v := range form {
+				val[k] = v
+			}
+			return redactBindData(val, redact)
+		}
+	}
+	if len(body) > DefaultAuditBodySize {
+		body = body[:DefaultAuditBodySize]
+	}
+	return string(body)
+}
+
 // NewBasicAuthFunc function creates middleware to implement
 // Basic auth authentication.
 //
@@ -58,6 +141,58 @@ func NewBasicAuthFunc(names map[string]string) Middleware {
 	}
 }
 
+// The NewBindDebugFunc function creates middleware to implement
+// output the data bound by ctx.Bind using the [eudore.LoggerDebug] log.
+//
+// The data is encoded as json, and the value of the field name in fields
+// or [DefaultBindDebugRedactFields] is replaced with
+// [DefaultBindDebugRedactValue].
+//
+// refer: [eudore.ContextKeyBindHook].
+//
+//go:noinline
+func NewBindDebugFunc(fields ...string) Middleware {
+	if len(fields) == 0 {
+		fields = DefaultBindDebugRedactFields
+	}
+	redact := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		redact[strings.ToLower(field)] = struct{}{}
+	}
+	hook := func(ctx eudore.Context, data any) {
+		body, err := json.Marshal(data)
+		if err != nil {
+			ctx.Debug("bind data:", err)
+			return
+		}
+		var val any
+		_ = json.Unmarshal(body, &val)
+		ctx.WithField("bind", redactBindData(val, redact)).Debug("bind data")
+	}
+	return func(ctx eudore.Context) {
+		ctx.SetValue(eudore.ContextKeyBindHook, hook)
+	}
+}
+
+func redactBindData(data any, redact map[string]struct{}) any {
+	switch val := data.(type) {
+	case map[string]any:
+		for k, v := range val {
+			_, ok := redact[strings.ToLower(k)]
+			if ok {
+				val[k] = DefaultBindDebugRedactValue
+			} else {
+				val[k] = redactBindData(v, redact)
+			}
+		}
+	case []any:
+		for i := range val {
+			val[i] = redactBindData(val[i], redact)
+		}
+	}
+	return data
+}
+
 // The NewBodyLimitFunc function creates middleware to implement
 // that limits the request body length.
 //
@@ -135,6 +270,56 @@ func (r *readerLength) Close() error {
 	return r.ReadCloser.Close()
 }
 
+// The NewCacheControlFunc function creates middleware to implement
+// setting [eudore.HeaderCacheControl] for the group routes.
+//
+// directive is the header value, such as "public, max-age=3600" or
+// "no-store", the value set by the handler is not overridden.
+//
+//go:noinline
+func NewCacheControlFunc(directive string) Middleware {
+	return func(ctx eudore.Context) {
+		w := &responseWriterCacheControl{ctx.Response(), directive}
+		ctx.SetResponse(w)
+		ctx.Next()
+		w.writeCacheControl()
+	}
+}
+
+type responseWriterCacheControl struct {
+	eudore.ResponseWriter
+	directive string
+}
+
+func (w *responseWriterCacheControl) Write(p []byte) (int, error) {
+	w.writeCacheControl()
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *responseWriterCacheControl) WriteString(p string) (int, error) {
+	w.writeCacheControl()
+	return w.ResponseWriter.WriteString(p)
+}
+
+func (w *responseWriterCacheControl) WriteHeader(code int) {
+	w.writeCacheControl()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriterCacheControl) Flush() {
+	w.writeCacheControl()
+	w.ResponseWriter.Flush()
+}
+
+func (w *responseWriterCacheControl) writeCacheControl() {
+	if w.directive != "" {
+		if w.Header().Get(eudore.HeaderCacheControl) == "" {
+			w.Header().Set(eudore.HeaderCacheControl, w.directive)
+		}
+		w.directive = ""
+	}
+}
+
 type csrf struct {
 	GetKeyFunc func(eudore.Context) string
 	Cookie     http.Cookie
@@ -220,8 +405,11 @@ func (ctx *contextWraper) GetHandlers() (int, []eudore.HandlerFunc) {
 // The Next method modifies the [eudore.Context] used by [HandlerFunc].
 func (ctx *contextWraper) Next() {
 	ctx.index++
-	for ctx.index < len(ctx.handlers) {
-		ctx.handlers[ctx.index](ctx)
+	for ctx.index < len(ctx.handlers) &&
+		ctx.index < eudore.DefaultContextMaxHandler {
+		if ctx.handlers[ctx.index] != nil {
+			ctx.handlers[ctx.index](ctx)
+		}
 		ctx.index++
 	}
 }
@@ -231,6 +419,72 @@ func (ctx *contextWraper) End() {
 	ctx.index = eudore.DefaultContextMaxHandler
 }
 
+// The NewDeprecationFunc function creates middleware to implement
+// that marks the route as deprecated.
+//
+// Set [eudore.HeaderDeprecation]; If sunset is not zero, set
+// [eudore.HeaderSunset] (RFC 8594); If link is not empty, set
+// [eudore.HeaderLink] with rel="sunset".
+//
+// Use [NewOptionDeprecationWarning] to log the deprecated route at
+// [eudore.LoggerWarning].
+func NewDeprecationFunc(sunset time.Time, link string, options ...Option,
+) Middleware {
+	opt := &deprecation{}
+	applyOption(opt, options)
+	if !sunset.IsZero() {
+		opt.Sunset = sunset.UTC().Format(http.TimeFormat)
+	}
+	if link != "" {
+		opt.Link = "<" + link + `>; rel="sunset"`
+	}
+	return func(ctx eudore.Context) {
+		h := ctx.Response().Header()
+		h.Set(eudore.HeaderDeprecation, "true")
+		if opt.Sunset != "" {
+			h.Set(eudore.HeaderSunset, opt.Sunset)
+		}
+		if opt.Link != "" {
+			h.Add(eudore.HeaderLink, opt.Link)
+		}
+		if opt.Warning {
+			ctx.Warningf("deprecated route %s %s is used",
+				ctx.Method(), ctx.GetParam(eudore.ParamRoute),
+			)
+		}
+	}
+}
+
+type deprecation struct {
+	Sunset  string
+	Link    string
+	Warning bool
+}
+
+// The NewHandlerDebugFunc function creates middleware to implement
+// output the names of the handlers that will execute after this middleware,
+// used to debug why a handler was not run.
+//
+// The names are joined with ", " and written to the
+// [eudore.HeaderXEudoreHandlers] header and the [eudore.LoggerDebug] log.
+//
+// If used as router middleware, it lists the route middleware and handlers;
+// if used as global middleware, it lists the global middleware.
+//
+//go:noinline
+func NewHandlerDebugFunc() Middleware {
+	return func(ctx eudore.Context) {
+		index, handlers := ctx.GetHandlers()
+		names := make([]string, 0, len(handlers))
+		for _, h := range handlers[index+1:] {
+			names = append(names, h.String())
+		}
+		chain := strings.Join(names, ", ")
+		ctx.SetHeader(eudore.HeaderXEudoreHandlers, chain)
+		ctx.WithField("handlers", names).Debug("handlers chain")
+	}
+}
+
 // The NewHealthCheckFunc function creates [eudore.HandlerFunc] to check
 // metadata health.
 //
@@ -348,17 +602,59 @@ func NewHeaderAddFunc(h http.Header) Middleware {
 // Append security headers: [eudore.HeaderXXSSProtection]
 // [eudore.HeaderXFrameOptions] [eudore.HeaderXContentTypeOptions].
 //
+// options: [NewOptionHeaderSecure].
+//
 //go:noinline
-func NewHeaderAddSecureFunc(h http.Header) Middleware {
-	header := http.Header{
-		eudore.HeaderXContentTypeOptions: []string{"nosniff"},
-		eudore.HeaderXFrameOptions:       []string{"SAMEORIGIN"},
-		eudore.HeaderXXSSProtection:      []string{"1; mode=block"},
+func NewHeaderAddSecureFunc(h http.Header, options ...Option) Middleware {
+	secure := &HeaderSecureConfig{
+		ContentTypeOptions: "nosniff",
+		FrameOptions:       "SAMEORIGIN",
+		XSSProtection:      "1; mode=block",
 	}
+	applyOption(secure, options)
+	header := secure.header()
 	headerCopy(header, h)
 	return NewHeaderAddFunc(header)
 }
 
+// HeaderSecureConfig defines the security headers of
+// [NewHeaderAddSecureFunc].
+//
+// If the value is "-", the header is omitted;
+// HSTS is added when HSTSMaxAge is greater than 0.
+type HeaderSecureConfig struct {
+	ContentTypeOptions    string
+	FrameOptions          string
+	XSSProtection         string
+	ContentSecurityPolicy string
+	ReferrerPolicy        string
+	HSTSMaxAge            int
+	HSTSIncludeSubDomains bool
+}
+
+func (secure *HeaderSecureConfig) header() http.Header {
+	header := http.Header{}
+	for k, v := range map[string]string{
+		eudore.HeaderXContentTypeOptions:   secure.ContentTypeOptions,
+		eudore.HeaderXFrameOptions:         secure.FrameOptions,
+		eudore.HeaderXXSSProtection:        secure.XSSProtection,
+		eudore.HeaderContentSecurityPolicy: secure.ContentSecurityPolicy,
+		eudore.HeaderReferrerPolicy:        secure.ReferrerPolicy,
+	} {
+		if v != "" && v != "-" {
+			header[k] = []string{v}
+		}
+	}
+	if secure.HSTSMaxAge > 0 {
+		hsts := "max-age=" + strconv.Itoa(secure.HSTSMaxAge)
+		if secure.HSTSIncludeSubDomains {
+			hsts += "; includeSubDomains"
+		}
+		header[eudore.HeaderStrictTransportSecurity] = []string{hsts}
+	}
+	return header
+}
+
 // The NewHeaderDeleteFunc function creates middleware to implement
 // delete request [http.Header].
 // If the IP is not in the sets, it delete the specified header to
@@ -410,20 +706,76 @@ func NewHeaderDeleteFunc(iplist, names []string) Middleware {
 	}
 }
 
+// The NewOptimisticLockFunc function creates middleware to implement
+// that uses [eudore.HeaderIfMatch] to prevent lost updates.
+//
+// For PUT and PATCH requests with [eudore.HeaderIfMatch], fn returns the
+// current resource ETag, if it does not match,
+// [eudore.StatusPreconditionFailed] is returned.
+// If the resource does not exist, fn returns empty string and
+// any If-Match fails, including "*".
+//
+// The ETag uses strong comparison, weak ETag never matches,
+// the handler uses [eudore.Context.SetETag] to return the new ETag.
+func NewOptimisticLockFunc(fn func(eudore.Context) string) Middleware {
+	return func(ctx eudore.Context) {
+		switch ctx.Method() {
+		case eudore.MethodPut, eudore.MethodPatch:
+		default:
+			return
+		}
+		match := ctx.GetHeader(eudore.HeaderIfMatch)
+		if match == "" {
+			return
+		}
+		if !matchETag(match, fn(ctx)) {
+			writePage(ctx, eudore.StatusPreconditionFailed,
+				DefaultPagePreconditionFailed, match,
+			)
+			ctx.End()
+		}
+	}
+}
+
+// The matchETag function uses strong comparison to match the
+// If-Match list.
+func matchETag(match, etag string) bool {
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	if etag[0] != '"' {
+		etag = `"` + etag + `"`
+	}
+	for _, tag := range strings.Split(match, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // The NewRecoveryFunc function creates middleware to implement recover errors
 // and return 500 and a detailed message.
 //
+// options: [NewOptionRecoveryCounter] [NewOptionRecoveryFatal].
+//
 //go:noinline
-func NewRecoveryFunc() Middleware {
+func NewRecoveryFunc(options ...Option) Middleware {
 	type m interface {
 		Unwrap() error
 		Stack() []string
 	}
+	opt := &recovery{}
+	applyOption(opt, options)
 	release := func(ctx eudore.Context) {
 		r := recover()
 		if r == nil {
 			return
 		}
+		if opt.Counter != nil {
+			opt.Counter(ctx.GetParam(eudore.ParamRoute))
+		}
 
 		var err error
 		stack := eudore.GetCallerStacks(3)
@@ -436,6 +788,13 @@ func NewRecoveryFunc() Middleware {
 		default:
 			err = fmt.Errorf("%v", r)
 		}
+		if opt.Fatal {
+			if opt.Stack {
+				err = recoveryError{err, stack}
+			}
+			ctx.WithField("stack", stack).Fatal(err)
+			return
+		}
 		if ctx.Response().Size() == 0 {
 			ctx.WriteStatus(eudore.StatusInternalServerError)
 			_ = ctx.Render(eudore.NewContextMessgae(ctx, err, stack))
@@ -449,6 +808,26 @@ func NewRecoveryFunc() Middleware {
 	}
 }
 
+type recovery struct {
+	Counter func(route string)
+	Fatal   bool
+	Stack   bool
+}
+
+// recoveryError uses the call stack as the message of [eudore.Context].Fatal.
+type recoveryError struct {
+	error
+	stack []string
+}
+
+func (err recoveryError) Unwrap() error {
+	return err.error
+}
+
+func (err recoveryError) Message() any {
+	return err.stack
+}
+
 // The NewRequestIDFunc function creates middleware to implement
 // setting [eudore.HeaderXRequestID]
 // and appends x-request-id to the log field.
@@ -477,6 +856,82 @@ func NewRequestIDFunc(fn func(eudore.Context) string) Middleware {
 	}
 }
 
+// The NewRequestLimitFunc function creates middleware to implement
+// that limits the request uri length and the header count and size.
+//
+// If the uri length exceeds uriLength, [eudore.StatusRequestURITooLong]
+// is returned; If the header count exceeds headerCount or the header size
+// exceeds headerSize, [eudore.StatusRequestHeaderFieldsTooLarge]
+// is returned. The limit less than or equal to 0 is disabled.
+//
+// The header size is the sum of key and value lengths,
+// [http.Server.MaxHeaderBytes] limits the bytes read by the server.
+//
+//go:noinline
+func NewRequestLimitFunc(uriLength, headerCount, headerSize int) Middleware {
+	return func(ctx eudore.Context) {
+		r := ctx.Request()
+		if uriLength > 0 && len(r.RequestURI) > uriLength {
+			writePage(ctx, eudore.StatusRequestURITooLong,
+				DefaultPageRequestURITooLong, strconv.Itoa(uriLength),
+			)
+			ctx.End()
+			return
+		}
+
+		var count, size int
+		for key, vals := range r.Header {
+			count += len(vals)
+			for _, val := range vals {
+				size += len(key) + len(val)
+			}
+		}
+		switch {
+		case headerCount > 0 && count > headerCount:
+			writePage(ctx, eudore.StatusRequestHeaderFieldsTooLarge,
+				DefaultPageRequestHeaderTooLarge,
+				fmt.Sprintf("count %d", headerCount),
+			)
+			ctx.End()
+		case headerSize > 0 && size > headerSize:
+			writePage(ctx, eudore.StatusRequestHeaderFieldsTooLarge,
+				DefaultPageRequestHeaderTooLarge,
+				fmt.Sprintf("size %d bytes", headerSize),
+			)
+			ctx.End()
+		}
+	}
+}
+
+// The NewRequireHeadersFunc function creates middleware to implement
+// that the request must carry the required headers.
+//
+// If the headers are missing or empty, [eudore.StatusBadRequest] is returned
+// and lists the missing headers.
+//
+// Use the Group or route path to add middleware to specific routes.
+func NewRequireHeadersFunc(names ...string) Middleware {
+	names = append([]string{}, names...)
+	for i := range names {
+		names[i] = http.CanonicalHeaderKey(names[i])
+	}
+	return func(ctx eudore.Context) {
+		h := ctx.Request().Header
+		var missing []string
+		for _, name := range names {
+			if h.Get(name) == "" {
+				missing = append(missing, name)
+			}
+		}
+		if missing != nil {
+			writePage(ctx, eudore.StatusBadRequest,
+				DefaultPageRequireHeaders, strings.Join(missing, ", "),
+			)
+			ctx.End()
+		}
+	}
+}
+
 // The NewRoutesFunc function creates middleware to implement
 // uses Routes to create [NewRouterFunc] middleware.
 func NewRoutesFunc(routes map[string]any) Middleware {
@@ -74,7 +74,7 @@ func NewCircuitBreakerFunc(options ...Option) Middleware {
 		if entry.OnAccess() {
 			// ignore panic
 			ctx.Next()
-			if ctx.Response().Status() < eudore.StatusInternalServerError {
+			if ctx.Status() < eudore.StatusInternalServerError {
 				if entry.OnSucceed() {
 					ctx.Infof("Breaker route %s change state to %s",
 						name, breakerStatues[breakerStatueClosed],
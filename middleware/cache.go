@@ -4,11 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"net"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/eudore/eudore"
@@ -48,10 +50,20 @@ type cacheResponse struct {
 // This middleware does not support cluster mode.
 // Cache requests are idempotent and do not rely on the cluster.
 //
+// The route tag [eudore.ParamCache] overrides the cache duration,
+// if the tag duration is not greater than 0, skip cache.
+//
+//	app.AddMiddleware(middleware.NewCacheFunc(time.Second))
+//	app.GetFunc("/api/* cache=30s", handler)
+//
 // options: [NewOptionKeyFunc] [NewOptionCacheClear].
 func NewCacheFunc(dura time.Duration, options ...Option) Middleware {
 	c := newCache(options)
 	return func(ctx eudore.Context) {
+		dura, ok := getCacheDuration(ctx, dura)
+		if !ok {
+			return
+		}
 		key := c.GetKeyFunc(ctx)
 		if key == "" {
 			return
@@ -92,6 +104,18 @@ func NewCacheFunc(dura time.Duration, options ...Option) Middleware {
 	}
 }
 
+func getCacheDuration(ctx eudore.Context, dura time.Duration,
+) (time.Duration, bool) {
+	tag := ctx.GetParam(eudore.ParamCache)
+	if tag != "" {
+		val, err := eudore.GetAnyByStringWithError[time.Duration](tag)
+		if err == nil {
+			return val, val > 0
+		}
+	}
+	return dura, true
+}
+
 func newCache(options []Option) *cache {
 	c := &cache{
 		waits:   make(map[string]*sync.WaitGroup),
@@ -155,6 +179,121 @@ func formatAccept(accept string) string {
 	return strings.Join(accepts, ",")
 }
 
+// IdempotencyStore defines the response storage of [NewIdempotencyFunc].
+//
+// LoadData returns nil if the key does not exist or has expired.
+type IdempotencyStore interface {
+	LoadData(key string) *IdempotencyResponse
+	SaveData(key string, val *IdempotencyResponse)
+}
+
+// IdempotencyResponse defines the response saved by [IdempotencyStore].
+type IdempotencyResponse = cacheResponse
+
+type idempotency struct {
+	storage    IdempotencyStore
+	GetKeyFunc func(ctx eudore.Context) string
+}
+
+// The NewIdempotencyFunc function creates middleware to implement
+// idempotent retries using [eudore.HeaderIdempotencyKey].
+//
+// If the request has a key, the response is saved to store within ttl,
+// requests with the same key, method, path, caller and body hash return
+// the saved response without executing the handler.
+// Requests with the key being processed return [eudore.StatusConflict].
+//
+// The caller defaults to the [eudore.ParamUserid] param or RealIP,
+// so that the key of other callers will not replay the saved response.
+//
+// The 50x response is not saved, so that the request can be retried.
+// If store is nil, the in-memory store is used and expired responses are
+// evicted every ttl.
+//
+// Cannot get response headers before this middleware.
+//
+// options: [NewOptionKeyFunc].
+func NewIdempotencyFunc(store IdempotencyStore, ttl time.Duration,
+	options ...Option,
+) Middleware {
+	if store == nil {
+		store = &cacheMap{cleanup: int64(ttl)}
+	}
+	idem := &idempotency{
+		storage: store,
+		GetKeyFunc: func(ctx eudore.Context) string {
+			user := ctx.GetParam(eudore.ParamUserid)
+			if user == "" {
+				user = ctx.RealIP()
+			}
+			return user
+		},
+	}
+	applyOption(idem, options)
+
+	var mu sync.Mutex
+	waits := make(map[string]struct{})
+	return func(ctx eudore.Context) {
+		key := ctx.GetHeader(eudore.HeaderIdempotencyKey)
+		if key == "" {
+			return
+		}
+		caller := idem.GetKeyFunc(ctx)
+		if caller == "" {
+			return
+		}
+		body, _ := ctx.Body()
+		fullkey := fmt.Sprintf("%s:%s:%s:%s:%x", key, ctx.Method(), ctx.Path(),
+			caller, sha256.Sum256(body),
+		)
+		data := idem.storage.LoadData(fullkey)
+		if data != nil {
+			headerCopy(ctx.Response().Header(), data.Header)
+			ctx.WriteHeader(data.Status)
+			if len(data.Body) != 0 {
+				_, _ = ctx.Write(data.Body)
+			}
+			ctx.End()
+			return
+		}
+
+		mu.Lock()
+		_, ok := waits[fullkey]
+		if ok {
+			mu.Unlock()
+			writePage(ctx, eudore.StatusConflict, DefaultPageIdempotencyConflict, key)
+			ctx.End()
+			return
+		}
+		waits[fullkey] = struct{}{}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(waits, fullkey)
+			mu.Unlock()
+		}()
+
+		w := &responseWriterCache{
+			ResponseWriter: ctx.Response(),
+			h:              make(http.Header),
+		}
+		ctx.SetResponse(w)
+		defer ctx.SetResponse(w.ResponseWriter)
+		ctx.Next()
+		if w.Size() == 0 {
+			headerCopy(w.ResponseWriter.Header(), w.h)
+		}
+		if w.Status() < 500 {
+			idem.storage.SaveData(fullkey, &cacheResponse{
+				Expired: time.Now().Add(ttl),
+				Status:  w.Status(),
+				Header:  w.h,
+				Body:    w.w.Bytes(),
+			})
+		}
+	}
+}
+
 // responseWriterCache defines cached response data.
 type responseWriterCache struct {
 	eudore.ResponseWriter
@@ -209,6 +348,10 @@ func (w *responseWriterCache) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 
 type cacheMap struct {
 	sync.Map
+	// cleanup is the interval to evict expired data when saving,
+	// and next is the UnixNano time of the next eviction.
+	cleanup int64
+	next    int64
 }
 
 func (c *cacheMap) LoadData(key string) *cacheResponse {
@@ -226,19 +369,31 @@ func (c *cacheMap) LoadData(key string) *cacheResponse {
 
 func (c *cacheMap) SaveData(key string, val *cacheResponse) {
 	c.Map.Store(key, val)
+	if c.cleanup > 0 {
+		now := time.Now()
+		next := atomic.LoadInt64(&c.next)
+		if now.UnixNano() > next &&
+			atomic.CompareAndSwapInt64(&c.next, next, now.UnixNano()+c.cleanup) {
+			c.deleteExpired(now)
+		}
+	}
+}
+
+func (c *cacheMap) deleteExpired(now time.Time) {
+	c.Map.Range(func(key, value any) bool {
+		item := value.(*cacheResponse)
+		if now.After(item.Expired) {
+			c.Map.Delete(key)
+		}
+		return true
+	})
 }
 
 func (c *cacheMap) cleanupExpired(ctx context.Context, t time.Duration) {
 	for {
 		select {
 		case now := <-time.After(t):
-			c.Map.Range(func(key, value any) bool {
-				item := value.(*cacheResponse)
-				if now.After(item.Expired) {
-					c.Map.Delete(key)
-				}
-				return true
-			})
+			c.deleteExpired(now)
 		case <-ctx.Done():
 			return
 		}
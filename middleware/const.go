@@ -23,7 +23,16 @@ const (
 )
 
 var (
-	DefaultCacheAllowAccept = map[string]struct{}{
+	// DefaultAuditBodySize global defines the max body summary size of
+	// [NewAuditFunc].
+	DefaultAuditBodySize = 1024
+	// DefaultBindDebugRedactFields global defines the field names masked
+	// by [NewBindDebugFunc] and [NewAuditFunc], case insensitive.
+	DefaultBindDebugRedactFields = []string{
+		"password", "passwd", "secret", "token", "authorization",
+	}
+	DefaultBindDebugRedactValue = "******"
+	DefaultCacheAllowAccept     = map[string]struct{}{
 		eudore.MimeAll:                 {},
 		eudore.MimeApplicationJSON:     {},
 		eudore.MimeApplicationProtobuf: {},
@@ -66,17 +75,22 @@ var (
 	DefaultLoggerOptionalFields = [...]string{
 		"remote-addr", "scheme", "querys", "byte-in",
 	}
-	DefaultPageAdmin          = adminStatic
-	DefaultPageBasicAuth      = "401 Unauthorized"
-	DefaultPageBodyLimit      = "413 Request Entity Too Large: body limit {{value}} bytes."
-	DefaultPageBlack          = "403 Forbidden: your IP is blacklisted {{value}}."
-	DefaultPageCircuitBreaker = "503 Service Unavailable: breaker triggered {{value}}."
-	DefaultPageCORS           = ""
-	DefaultPageCSRF           = "403 Forbidden: invalid CSRF token {{value}}."
-	DefaultPageHealth         = "unhealthy: {{value}}"
-	DefaultPageRate           = "429 Too Many Requests: rate limit exceeded {{value}}."
-	DefaultPageReferer        = "403 Forbidden: invalid Referer header {{value}}."
-	DefaultPageTimeout        = "503 Service Unavailable"
+	DefaultPageAdmin                 = adminStatic
+	DefaultPageBasicAuth             = "401 Unauthorized"
+	DefaultPageBodyLimit             = "413 Request Entity Too Large: body limit {{value}} bytes."
+	DefaultPageBlack                 = "403 Forbidden: your IP is blacklisted {{value}}."
+	DefaultPageCircuitBreaker        = "503 Service Unavailable: breaker triggered {{value}}."
+	DefaultPageCORS                  = ""
+	DefaultPageCSRF                  = "403 Forbidden: invalid CSRF token {{value}}."
+	DefaultPageHealth                = "unhealthy: {{value}}"
+	DefaultPageIdempotencyConflict   = "409 Conflict: idempotency key {{value}} is being processed."
+	DefaultPagePreconditionFailed    = "412 Precondition Failed: If-Match {{value}} does not match the current resource."
+	DefaultPageRate                  = "429 Too Many Requests: rate limit exceeded {{value}}."
+	DefaultPageReferer               = "403 Forbidden: invalid Referer header {{value}}."
+	DefaultPageRequestHeaderTooLarge = "431 Request Header Fields Too Large: header limit {{value}}."
+	DefaultPageRequestURITooLong     = "414 URI Too Long: uri limit {{value}} bytes."
+	DefaultPageRequireHeaders        = "400 Bad Request: missing required headers {{value}}."
+	DefaultPageTimeout               = "503 Service Unavailable"
 	// DefaultPProfHandlers global defines pprof route.
 	DefaultPProfHandlers = map[string]http.Handler{
 		"cmdline":      http.HandlerFunc(pprof.Cmdline),
@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/eudore/eudore"
@@ -12,12 +14,24 @@ import (
 // output access logs.
 //
 // Output these fields [DefaultLoggerFixedFields] by default,
+// the route field is the matched route pattern [eudore.ParamRoute],
+// such as /users/:id, and is empty when no route matches.
 //
 // You can set params to customize additional fields:
 // param:<name> request:<header-name> response:<header-name> cookie:<name>
 //
 // Default params: response:X-Request-Id response:X-Trace-Id
 //
+// You can set params to filter noisy paths: skip:<path> debug:<path>,
+// skip does not output access logs, debug outputs access logs using
+// [eudore.LoggerDebug] if the status is not 50x.
+// If the path ends with '*', it matches by prefix.
+//
+// You can set params to sample access logs by status class:
+// sample:<class>=<rate>, such as sample:2xx=0.1 sample:3xx=0.5,
+// the rate is between 0 and 1, and unset classes are fully output.
+// The sampling is evenly distributed by count, not random.
+//
 // If the response status is 50x, the output log level is [eudore.LoggerError].
 //
 // Note that if diff params output duplicate log fields,
@@ -25,6 +39,9 @@ import (
 //
 // This middleware needs to be placed before [NewRecoveryFunc],
 // and does not handle panic situations.
+//
+// If the request uses [NewLoggerGroupFunc], the access log uses the group
+// logger.
 func NewLoggerFunc(log eudore.Logger, params ...string) Middleware {
 	call := loggerInit(log, params)
 	return func(ctx eudore.Context) {
@@ -34,6 +51,27 @@ func NewLoggerFunc(log eudore.Logger, params ...string) Middleware {
 	}
 }
 
+// The NewLoggerGroupFunc function creates middleware to implement
+// associate a [eudore.Logger] with the router group.
+//
+// If group is not empty, log adds the field group.
+// Set the log to [eudore.ContextKeyLogger] and
+// [eudore.ContextKeyLoggerGroup], the handler logs and [NewLoggerFunc]
+// access logs carry the fields of log.
+//
+// log should be derived from the access logger using WithField,
+// otherwise access logs are output to log.
+func NewLoggerGroupFunc(group string, log eudore.Logger) Middleware {
+	if group != "" {
+		log = log.WithField("group", group)
+	}
+	log = log.WithField("logger", true)
+	return func(ctx eudore.Context) {
+		ctx.SetValue(eudore.ContextKeyLogger, log)
+		ctx.SetValue(eudore.ContextKeyLoggerGroup, log)
+	}
+}
+
 // The NewLoggerWithEventFunc function creates middleware to implement handle
 // Sever-send-event output access logs.
 //
@@ -89,14 +127,37 @@ func loggerInit(log eudore.Logger, params []string) func(eudore.Context, time.Ti
 		eudore.ParamDepth,
 		eudore.DefaultLoggerDepthKindDisable,
 	).WithField("logger", true)
+	samples, params := loggerSamples(params)
+	filters, params := loggerFilters(params)
 	if params == nil {
 		params = []string{"response:X-Request-Id", "response:X-Trace-Id"}
 	}
 	return func(ctx eudore.Context, now time.Time) {
 		r, w := ctx.Request(), ctx.Response()
+		debug := false
+		for _, filter := range filters {
+			if filter.match(r.URL.Path) {
+				if !filter.debug {
+					return
+				}
+				debug = true
+				break
+			}
+		}
 		status := w.Status()
+		if samples != nil && !samples.match(status) {
+			return
+		}
+		out := log
+		group, ok := ctx.Value(eudore.ContextKeyLoggerGroup).(eudore.Logger)
+		if ok {
+			out = group.WithField(
+				eudore.ParamDepth,
+				eudore.DefaultLoggerDepthKindDisable,
+			).WithField("logger", true)
+		}
 		// const fields
-		out := log.WithField("time", now).
+		out = out.WithField("time", now).
 			WithFields(DefaultLoggerFixedFields[:], []any{
 				r.Host, r.Method, r.URL.Path, r.Proto,
 				ctx.RealIP(), ctx.GetParam(eudore.ParamRoute),
@@ -131,9 +192,12 @@ func loggerInit(log eudore.Logger, params []string) func(eudore.Context, time.Ti
 			}
 		}
 
-		if status < 500 {
+		switch {
+		case status < 500 && debug:
+			out.Debug()
+		case status < 500:
 			out.Info()
-		} else {
+		default:
 			if err := ctx.Err(); err != nil {
 				out = out.WithField("error", err.Error())
 			}
@@ -142,6 +206,98 @@ func loggerInit(log eudore.Logger, params []string) func(eudore.Context, time.Ti
 	}
 }
 
+type loggerFilter struct {
+	path   string
+	prefix bool
+	debug  bool
+}
+
+// The loggerFilters function splits skip: and debug: params from params,
+// returns nil params if no other params.
+func loggerFilters(params []string) ([]loggerFilter, []string) {
+	var filters []loggerFilter
+	var fields []string
+	for _, param := range params {
+		var filter loggerFilter
+		switch {
+		case strings.HasPrefix(param, "skip:"):
+			filter.path = param[5:]
+		case strings.HasPrefix(param, "debug:"):
+			filter.path = param[6:]
+			filter.debug = true
+		default:
+			fields = append(fields, param)
+			continue
+		}
+		if strings.HasSuffix(filter.path, "*") {
+			filter.path = filter.path[:len(filter.path)-1]
+			filter.prefix = true
+		}
+		filters = append(filters, filter)
+	}
+	if filters == nil {
+		return nil, params
+	}
+	return filters, fields
+}
+
+type loggerSample struct {
+	rates  [6]float64
+	counts [6]uint64
+}
+
+// The loggerSamples function splits sample: params from params,
+// returns nil samples if there is no sample param.
+func loggerSamples(params []string) (*loggerSample, []string) {
+	var sample *loggerSample
+	var fields []string
+	for _, param := range params {
+		if !strings.HasPrefix(param, "sample:") {
+			fields = append(fields, param)
+			continue
+		}
+		class, val, _ := strings.Cut(param[7:], "=")
+		rate, err := strconv.ParseFloat(val, 64)
+		if len(class) != 3 || class[0] < '1' || class[0] > '5' ||
+			strings.ToLower(class[1:]) != "xx" || err != nil {
+			continue
+		}
+		if sample == nil {
+			sample = &loggerSample{rates: [6]float64{1, 1, 1, 1, 1, 1}}
+		}
+		switch {
+		case rate < 0:
+			rate = 0
+		case rate > 1:
+			rate = 1
+		}
+		sample.rates[class[0]-'0'] = rate
+	}
+	if sample == nil {
+		return nil, params
+	}
+	return sample, fields
+}
+
+// The match method counts the status class and returns whether the count
+// reaches the next sample point.
+func (sample *loggerSample) match(status int) bool {
+	class := status / 100
+	if class < 1 || class > 5 || sample.rates[class] == 1 {
+		return true
+	}
+	rate := sample.rates[class]
+	n := atomic.AddUint64(&sample.counts[class], 1)
+	return uint64(float64(n)*rate) != uint64(float64(n-1)*rate)
+}
+
+func (filter loggerFilter) match(path string) bool {
+	if filter.prefix {
+		return strings.HasPrefix(path, filter.path)
+	}
+	return path == filter.path
+}
+
 // can inline with cost 70.
 func loggerValue(log eudore.Logger, key, val string) eudore.Logger {
 	if val == "" {
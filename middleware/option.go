@@ -18,7 +18,7 @@ type Option func(any)
 // the corresponding middleware will be skipped.
 //
 // middleware: [NewCSRFFunc] [NewCircuitBreakerFunc] [NewCacheFunc]
-// [NewRateRequestFunc] [NewRateSpeedFunc].
+// [NewIdempotencyFunc] [NewRateRequestFunc] [NewRateSpeedFunc].
 func NewOptionKeyFunc(fn func(eudore.Context) string) Option {
 	return func(data any) {
 		switch v := data.(type) {
@@ -30,6 +30,8 @@ func NewOptionKeyFunc(fn func(eudore.Context) string) Option {
 			v.GetKeyFunc = fn
 		case *csrf:
 			v.GetKeyFunc = fn
+		case *idempotency:
+			v.GetKeyFunc = fn
 		}
 	}
 }
@@ -38,10 +40,14 @@ func NewOptionKeyFunc(fn func(eudore.Context) string) Option {
 //
 // NewBlackFunc middleware will add [sync.RWMutex].
 //
-// middleware: [NewCircuitBreakerFunc] [NewBlackListFunc].
+// NewCORSFunc middleware uses router to get preflight allowed methods.
+//
+// middleware: [NewCircuitBreakerFunc] [NewBlackListFunc] [NewCORSFunc].
 func NewOptionRouter(router eudore.Router) Option {
 	return func(data any) {
 		switch v := data.(type) {
+		case *cors:
+			v.Router = router
 		case *breaker:
 			router.GetFunc("/breaker/data", v.data)
 			router.GetFunc("/breaker/:id", v.get)
@@ -112,6 +118,69 @@ func NewOptionRateCleanup(ctx context.Context, t time.Duration, less int,
 	}
 }
 
+// NewOptionRecoveryCounter function creates Recovery option to count panics,
+// fn is called with the route when a panic is recovered.
+//
+// example: counter.WithLabelValues(route).Inc().
+func NewOptionRecoveryCounter(fn func(route string)) Option {
+	return func(data any) {
+		v, ok := data.(*recovery)
+		if ok {
+			v.Counter = fn
+		}
+	}
+}
+
+// NewOptionRecoveryFatal function creates Recovery option to convert the
+// recovered value into an error and respond by [eudore.Context].Fatal,
+// the status uses the Status method of the error, or 500.
+//
+// If stack is true, the message of the response is the call stack,
+// only used for debugging.
+func NewOptionRecoveryFatal(stack bool) Option {
+	return func(data any) {
+		v, ok := data.(*recovery)
+		if ok {
+			v.Fatal = true
+			v.Stack = stack
+		}
+	}
+}
+
+// NewOptionDeprecationWarning function creates Deprecation option to log
+// the deprecated route at [eudore.LoggerWarning].
+func NewOptionDeprecationWarning() Option {
+	return func(data any) {
+		v, ok := data.(*deprecation)
+		if ok {
+			v.Warning = true
+		}
+	}
+}
+
+// NewOptionHeaderSecure function creates HeaderAddSecure option to modify
+// security headers, empty values keep the default value.
+func NewOptionHeaderSecure(conf HeaderSecureConfig) Option {
+	return func(data any) {
+		v, ok := data.(*HeaderSecureConfig)
+		if ok {
+			for _, s := range []struct{ dst, src *string }{
+				{&v.ContentTypeOptions, &conf.ContentTypeOptions},
+				{&v.FrameOptions, &conf.FrameOptions},
+				{&v.XSSProtection, &conf.XSSProtection},
+				{&v.ContentSecurityPolicy, &conf.ContentSecurityPolicy},
+				{&v.ReferrerPolicy, &conf.ReferrerPolicy},
+			} {
+				if *s.src != "" {
+					*s.dst = *s.src
+				}
+			}
+			v.HSTSMaxAge = conf.HSTSMaxAge
+			v.HSTSIncludeSubDomains = conf.HSTSIncludeSubDomains
+		}
+	}
+}
+
 func applyOption(data any, options []Option) {
 	for i := range options {
 		options[i](data)
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"runtime"
 	"runtime/pprof"
 	"sort"
 	"strconv"
@@ -36,6 +37,37 @@ func NewPProfFunc() Middleware {
 	}
 }
 
+// The NewHandlerGoroutineDump function creates a handler that dumps the
+// stacks of all goroutines using [runtime.Stack].
+//
+// If the query log is not empty, the dump is written to the [eudore.Logger]
+// instead of the response, used to capture state during an incident
+// without exposing pprof.
+//
+//go:noinline
+func NewHandlerGoroutineDump() Middleware {
+	return func(ctx eudore.Context) {
+		buf := make([]byte, 1<<16)
+		for {
+			n := runtime.Stack(buf, true)
+			if n < len(buf) || len(buf) >= 64<<20 {
+				buf = buf[:n]
+				break
+			}
+			buf = make([]byte, 2*len(buf))
+		}
+
+		if ctx.GetQuery("log") != "" {
+			ctx.WithField("goroutines", string(buf)).Warning("goroutine dump")
+			ctx.SetHeader(headerContentType, eudore.MimeTextPlainCharsetUtf8)
+			_, _ = fmt.Fprintf(ctx, "goroutine dump %d bytes written to logger", len(buf))
+			return
+		}
+		ctx.SetHeader(headerContentType, eudore.MimeTextPlainCharsetUtf8)
+		_, _ = ctx.Write(buf)
+	}
+}
+
 type profile struct {
 	Name  string `json:"name"`
 	Href  string `json:"href"`
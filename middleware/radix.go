@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/http"
 	"net/textproto"
+	"regexp"
 	"strings"
 
 	"github.com/eudore/eudore"
@@ -22,9 +23,16 @@ var (
 // after successful cross-domain verification.
 //
 // If pattens is empty, any origin is allowed.
+// If the pattern starts with '^', it is matched as a regular expression,
+// otherwise * matches the next character . or / or :, last * matches to the end.
+//
+// The allowed origin is echoed back in
+// [eudore.HeaderAccessControlAllowOrigin] instead of *.
 //
 // If [eudore.HeaderAccessControlAllowMethods] or
-// [eudore.HeaderAccessControlAllowHeaders] is empty, set it to *.
+// [eudore.HeaderAccessControlAllowHeaders] is empty, set it to *;
+// if [eudore.HeaderAccessControlAllowCredentials] is true, * is invalid and
+// the preflight request method and headers are echoed back.
 //
 // When cors registration is not a global middleware,
 // you need to register Options /* or 404 method for the last time,
@@ -38,22 +46,38 @@ var (
 //		"Access-Control-Max-Age":           "1000",
 //	}))
 //
-// * matches the next character . or / or :, last * matches to the end.
-func NewCORSFunc(patterns []string, headers map[string]string) Middleware {
-	corsHeaders := make(http.Header, len(headers))
-	corsHeaders[eudore.HeaderAccessControlAllowMethods] = []string{"*"}
-	corsHeaders[eudore.HeaderAccessControlAllowHeaders] = []string{"*"}
+// options: [NewOptionRouter] use the route allowed methods as
+// [eudore.HeaderAccessControlAllowMethods].
+func NewCORSFunc(patterns []string, headers map[string]string,
+	options ...Option,
+) Middleware {
+	c := &cors{Headers: make(http.Header, len(headers))}
 	for k, v := range headers {
-		corsHeaders[textproto.CanonicalMIMEHeaderKey(k)] = []string{v}
+		c.Headers[textproto.CanonicalMIMEHeaderKey(k)] = []string{v}
+	}
+	c.Credentials = c.Headers.Get(eudore.HeaderAccessControlAllowCredentials) == "true"
+	c.Methods = c.Headers.Get(eudore.HeaderAccessControlAllowMethods) == ""
+	if c.Methods {
+		c.Headers[eudore.HeaderAccessControlAllowMethods] = []string{"*"}
+	}
+	if c.Headers.Get(eudore.HeaderAccessControlAllowHeaders) == "" {
+		c.Headers[eudore.HeaderAccessControlAllowHeaders] = []string{"*"}
+		c.RequestHeaders = c.Credentials
 	}
 
-	node := new(radixNode[byte])
+	c.Origins = new(radixNode[byte])
 	if patterns == nil {
 		patterns = []string{"*"}
 	}
 	for _, pattern := range patterns {
-		node.insert(pattern, stateTrue)
+		if strings.HasPrefix(pattern, "^") {
+			c.Regexps = append(c.Regexps, regexp.MustCompile(pattern))
+		} else {
+			c.Origins.insert(pattern, stateTrue)
+		}
 	}
+	applyOption(c, options)
+
 	return func(ctx eudore.Context) {
 		origin := ctx.GetHeader(eudore.HeaderOrigin)
 		host := trimScheme(origin)
@@ -65,7 +89,7 @@ func NewCORSFunc(patterns []string, headers map[string]string) Middleware {
 
 		h := ctx.Response().Header()
 		headerVary(h, eudore.HeaderOrigin)
-		if node.lookNode(host) == nil {
+		if !c.match(origin, host) {
 			writePage(ctx, eudore.StatusForbidden, DefaultPageCORS, host)
 			ctx.End()
 			return
@@ -73,13 +97,61 @@ func NewCORSFunc(patterns []string, headers map[string]string) Middleware {
 
 		h.Add(eudore.HeaderAccessControlAllowOrigin, origin)
 		if ctx.Method() == eudore.MethodOptions {
-			headerCopy(h, corsHeaders)
+			headerCopy(h, c.Headers)
+			c.preflight(ctx, h)
 			ctx.WriteHeader(eudore.StatusNoContent)
 			ctx.End()
 		}
 	}
 }
 
+type cors struct {
+	Origins        *radixNode[byte]
+	Regexps        []*regexp.Regexp
+	Headers        http.Header
+	Router         eudore.RouterCore
+	Credentials    bool
+	Methods        bool
+	RequestHeaders bool
+}
+
+func (c *cors) match(origin, host string) bool {
+	if c.Origins.lookNode(host) != nil {
+		return true
+	}
+	for _, reg := range c.Regexps {
+		if reg.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cors) preflight(ctx eudore.Context, h http.Header) {
+	if c.Methods {
+		switch {
+		case c.Router != nil:
+			params := &eudore.Params{eudore.ParamRoute, ""}
+			c.Router.Match("", ctx.Path(), params)
+			allow := params.Get(eudore.ParamAllow)
+			if allow != "" {
+				h.Set(eudore.HeaderAccessControlAllowMethods, allow)
+			}
+		case c.Credentials:
+			method := ctx.GetHeader(eudore.HeaderAccessControlRequestMethod)
+			if method != "" {
+				h.Set(eudore.HeaderAccessControlAllowMethods, method)
+			}
+		}
+	}
+	if c.RequestHeaders {
+		headers := ctx.GetHeader(eudore.HeaderAccessControlRequestHeaders)
+		if headers != "" {
+			h.Set(eudore.HeaderAccessControlAllowHeaders, headers)
+		}
+	}
+}
+
 // can inline with cost 49.
 func trimScheme(host string) string {
 	switch {
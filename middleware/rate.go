@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +35,12 @@ type rateBucket struct {
 //
 // This middleware does not support cluster mode.
 //
+// The route tag [eudore.ParamRateLimit] overrides the speed and total,
+// the format is 'speed' or 'speed,total', and each route uses its own bucket.
+//
+//	app.AddMiddleware(middleware.NewRateRequestFunc(10, 30))
+//	app.GetFunc("/api/* ratelimit=1,3", handler)
+//
 // options: [NewOptionKeyFunc] [NewOptionRateCleanup].
 func NewRateRequestFunc(speed, total int64, options ...Option) Middleware {
 	r := newRate(speed, total, options)
@@ -42,16 +49,17 @@ func NewRateRequestFunc(speed, total int64, options ...Option) Middleware {
 		if key == "" {
 			return
 		}
-		now, at, ok := r.GetVisitor(key).Allow()
+		bucket := r.getRouteVisitor(ctx, key)
+		now, at, ok := bucket.Allow()
 		state := now - at
-		ctx.SetHeader(eudore.HeaderXRateLimit, fi64(r.total/r.speed))
-		ctx.SetHeader(eudore.HeaderXRateReset, fi64((at+r.total)/1000000000))
+		ctx.SetHeader(eudore.HeaderXRateLimit, fi64(bucket.max/bucket.speed))
+		ctx.SetHeader(eudore.HeaderXRateReset, fi64((at+bucket.max)/1000000000))
 		if ok {
-			ctx.SetHeader(eudore.HeaderXRateRemaining, fi64(state/r.speed))
+			ctx.SetHeader(eudore.HeaderXRateRemaining, fi64(state/bucket.speed))
 			return
 		}
 
-		retry := int((r.speed - state) / 1000000000)
+		retry := int((bucket.speed - state) / 1000000000)
 		if retry < DefaultRateRetryMin {
 			retry = DefaultRateRetryMin
 		}
@@ -119,14 +127,36 @@ func newRate(speed, total int64, options []Option) *rate {
 
 // The GetVisitor method gets the rateBucket through the key.
 func (r *rate) GetVisitor(key string) *rateBucket {
+	return r.getVisitor(key, r.speed, r.total)
+}
+
+// The getRouteVisitor method gets the rateBucket using the route tag
+// [eudore.ParamRateLimit], and the bucket key is prefixed with route.
+func (r *rate) getRouteVisitor(ctx eudore.Context, key string) *rateBucket {
+	tag := ctx.GetParam(eudore.ParamRateLimit)
+	if tag != "" {
+		s, t, _ := strings.Cut(tag, ",")
+		speed := eudore.GetAnyByString[int64](s)
+		total := eudore.GetAnyByString(t, speed)
+		if speed > 0 && total > 0 {
+			speed = int64(time.Second) / speed
+			return r.getVisitor(ctx.GetParam(eudore.ParamRoute)+" "+key,
+				speed, speed*total,
+			)
+		}
+	}
+	return r.getVisitor(key, r.speed, r.total)
+}
+
+func (r *rate) getVisitor(key string, speed, total int64) *rateBucket {
 	r.mu.RLock()
 	v, exists := r.visitors[key]
 	r.mu.RUnlock()
 	if !exists {
 		limiter := &rateBucket{
-			speed: r.speed,
-			max:   r.total,
-			last:  time.Now().UnixNano() - r.total,
+			speed: speed,
+			max:   total,
+			last:  time.Now().UnixNano() - total,
 		}
 		r.mu.Lock()
 		r.visitors[key] = limiter
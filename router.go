@@ -53,6 +53,12 @@ type Router interface {
 	// The method is TEST, which will output the debug information related to
 	// the route registration,
 	// but will not perform the registration behavior.
+	//
+	// The route params declared in path are route tags, after [RouterCore]
+	// Match, middleware and handler can use ctx.GetParam to read them.
+	// The global middleware of [App] runs before Match and cannot read them.
+	//
+	//	app.GetFunc("/api/* cache=30s ratelimit=100", handler)
 	AddHandler(method string, path string, fn ...any) error
 
 	// AddController method registers the [Controller], and the controller
@@ -63,6 +69,13 @@ type Router interface {
 	// [HandlerExtender] will convert any type Handlers into []HandlerFunc.
 	//
 	// If the first parameter is a string type, it is used as a Group route.
+	//
+	// The route can use the [ParamPriority] param to set the phase
+	// name in [DefaultRouterMiddlewarePhases] or a number,
+	// middleware is sorted by priority first and then by adding order.
+	//
+	//	app.AddMiddleware(" priority=auth", middleware.NewBasicAuthFunc(nil))
+	//	app.AddMiddleware("/api priority=10", middleware.NewLoggerFunc(app))
 	AddMiddleware(fn ...any) error
 
 	// AddHandlerExtend method adds an extension function to the
@@ -374,10 +387,13 @@ func getControllerPathName(ctl Controller) string {
 
 func (r *routerStd) AddMiddleware(hs ...any) error {
 	path := r.GroupParams.Get("route")
+	priority := ""
 	if len(hs) > 1 {
 		route, ok := hs[0].(string)
 		if ok {
-			path += route
+			params := NewParamsRoute(route)
+			path += params.Get(ParamRoute)
+			priority = params.Get(ParamPriority)
 			hs = hs[1:]
 		}
 	}
@@ -391,7 +407,7 @@ func (r *routerStd) AddMiddleware(hs ...any) error {
 		return err
 	}
 
-	r.Middlewares.Insert(path, handlers)
+	r.Middlewares.Insert(path, getMiddlewarePriority(priority), handlers)
 	r.RouterCore.HandleFunc("Middlewares", path, handlers)
 	log := r.getLogger(routerLoggerMiddleware, depth)
 	if path != "" {
@@ -47,6 +47,11 @@ type nodeMuxHandler struct {
 //
 // The [DefaultRouterAnyMethod] [DefaultRouterAllMethod] data will be copied
 // when created.
+//
+// The path segment ':name' matches a param, '*name' matches the remaining
+// path as the wildcard param name, '*' uses the param name '*'.
+// The handlers should use [ParamWildcard] and then '*' to get the
+// wildcard value, such as [NewHandlerFileSystem].
 func NewRouterCoreMux() RouterCore {
 	return &routerCoreMux{
 		Root:        &nodeMux{},
@@ -108,6 +113,14 @@ func (mux *routerCoreMux) Match(method, path string, params *Params,
 	node := mux.Root.lookNode(path, params)
 	// 404
 	if node == nil {
+		if DefaultRouterSuggestion {
+			routes := mux.Root.lookSuggestion(path, nil)
+			if routes != nil {
+				*params = params.Add(ParamRouteSuggest,
+					strings.Join(routes, ", "),
+				)
+			}
+		}
 		*params = params.Add(mux.Params404...)
 		return mux.Handler404
 	}
@@ -294,6 +307,48 @@ func (node *nodeMux) insertNodeConst(next *nodeMux) *nodeMux {
 	return next
 }
 
+// The lookSuggestion method traverses all routes and returns the routes
+// with only one constant segment different from the path.
+func (node *nodeMux) lookSuggestion(path string, routes []string) []string {
+	if node.route != "" && (node.handlers != nil || node.anyHandler != nil) &&
+		isRouteSuggestion(node.route, path) {
+		routes = append(routes, node.route)
+	}
+	for _, nodes := range [...][]*nodeMux{
+		node.childc, node.childpv, node.childp, node.childwv,
+	} {
+		for _, child := range nodes {
+			routes = child.lookSuggestion(path, routes)
+		}
+	}
+	if node.childw != nil {
+		routes = node.childw.lookSuggestion(path, routes)
+	}
+	return routes
+}
+
+func isRouteSuggestion(route, path string) bool {
+	routes := strings.Split(route, "/")
+	paths := strings.Split(path, "/")
+	if len(routes) != len(paths) {
+		return false
+	}
+	var diff int
+	for i := range routes {
+		switch {
+		case strings.HasPrefix(routes[i], "*"):
+			return false
+		case strings.HasPrefix(routes[i], ":"):
+			if paths[i] == "" {
+				diff++
+			}
+		case routes[i] != paths[i]:
+			diff++
+		}
+	}
+	return diff == 1
+}
+
 //nolint:cyclop,gocyclo,gocognit
 func (node *nodeMux) lookNode(path string, params *Params) *nodeMux {
 	if path != "" {
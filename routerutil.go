@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -80,20 +81,23 @@ type middlewareTree struct {
 type middlewareNode = radixNode[*middlewareDatas, middlewareDatas]
 
 type middlewareData struct {
+	priority int
 	index    int
 	handlers HandlerFuncs
 }
 type middlewareDatas []middlewareData
 
 func (data *middlewareDatas) Insert(i ...any) error {
-	*data = append(*data, middlewareData{i[0].(int), i[1].(HandlerFuncs)})
+	*data = append(*data, middlewareData{
+		i[0].(int), i[1].(int), i[2].(HandlerFuncs),
+	})
 	return nil
 }
 
 // The Insert method implements middlewareNode to add a child node.
-func (t *middlewareTree) Insert(path string, val []HandlerFunc) {
+func (t *middlewareTree) Insert(path string, priority int, val []HandlerFunc) {
 	t.index++
-	_ = t.root.insert(path, t.index, val)
+	_ = t.root.insert(path, priority, t.index, val)
 }
 
 // Lookup Find if seachKey exist in current trie tree and return its value.
@@ -103,6 +107,9 @@ func (t *middlewareTree) Lookup(path string) []HandlerFunc {
 		data = append(data, *v...)
 	}
 	sort.Slice(data, func(i, j int) bool {
+		if data[i].priority != data[j].priority {
+			return data[i].priority < data[j].priority
+		}
 		return data[i].index < data[j].index
 	})
 
@@ -113,6 +120,20 @@ func (t *middlewareTree) Lookup(path string) []HandlerFunc {
 	return handlers
 }
 
+// The getMiddlewarePriority function gets the priority using the phase name
+// in [DefaultRouterMiddlewarePhases] or number, default is route phase.
+func getMiddlewarePriority(priority string) int {
+	val, ok := DefaultRouterMiddlewarePhases[priority]
+	if ok {
+		return val
+	}
+	val, err := strconv.Atoi(priority)
+	if err == nil {
+		return val
+	}
+	return DefaultRouterMiddlewarePhases["route"]
+}
+
 // The clone method deeply copies this middleware node.
 func (t *middlewareTree) clone() *middlewareTree {
 	return &middlewareTree{
@@ -298,3 +319,83 @@ func indexBytes(path string) int {
 	}
 	return pos
 }
+
+// RoutePath defines the route path builder,
+// which builds the pattern string using static segments and params,
+// and creates the param accessor [RouteParam] checked by the pattern.
+//
+// RoutePath is immutable, each method returns a new RoutePath.
+type RoutePath struct {
+	path   string
+	params []string
+}
+
+// RouteParam defines the param accessor created by [RoutePath].Param.
+type RouteParam string
+
+// The NewRoutePath function creates [RoutePath] using the static path prefix.
+func NewRoutePath(path string) RoutePath {
+	return RoutePath{path: path}
+}
+
+// The Static method appends the static path segment.
+func (r RoutePath) Static(path string) RoutePath {
+	return r.append(strings.TrimPrefix(path, "/"), "")
+}
+
+// The WithParam method appends the ':name' param segment,
+// check is the optional check function name, such as 'num'.
+func (r RoutePath) WithParam(name string, check ...string) RoutePath {
+	return r.append(":"+name+routePathCheck(check), name)
+}
+
+// The WithWildcard method appends the '*name' wildcard segment,
+// the wildcard matches the remaining path, the empty name is '*'.
+func (r RoutePath) WithWildcard(name string, check ...string) RoutePath {
+	if name == "" || name == "*" {
+		return r.append("*", "*")
+	}
+	return r.append("*"+name+routePathCheck(check), name)
+}
+
+// The Param method returns the accessor of the param name,
+// panics if the param is not defined in the path.
+func (r RoutePath) Param(name string) RouteParam {
+	if sliceIndex(r.params, name) == -1 {
+		panic(fmt.Errorf(ErrRouterPathParamUndefined, name, r.path))
+	}
+	return RouteParam(name)
+}
+
+// The String method returns the route pattern string.
+func (r RoutePath) String() string {
+	return r.path
+}
+
+func (r RoutePath) append(path, name string) RoutePath {
+	params := r.params[:len(r.params):len(r.params)]
+	if name != "" {
+		params = append(params, name)
+	}
+	return RoutePath{
+		path:   strings.TrimSuffix(r.path, "/") + "/" + path,
+		params: params,
+	}
+}
+
+func routePathCheck(check []string) string {
+	if len(check) > 0 && check[0] != "" {
+		return "|" + check[0]
+	}
+	return ""
+}
+
+// The Get method gets the param value from [Context].
+func (p RouteParam) Get(ctx Context) string {
+	return ctx.GetParam(string(p))
+}
+
+// The String method returns the param name.
+func (p RouteParam) String() string {
+	return string(p)
+}
@@ -5,11 +5,13 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -26,6 +28,16 @@ func (key contextKey) String() string {
 	return key.name
 }
 
+// The GetContextValue function gets the value of the key from the [Context]
+// and asserts it to type T, and returns zero value if it does not exist.
+//
+//	ctx.SetValue(NewContextKey("user"), user)
+//	user := GetContextValue[*User](ctx, NewContextKey("user"))
+func GetContextValue[T any](ctx interface{ Value(key any) any }, key any) T {
+	val, _ := ctx.Value(key).(T)
+	return val
+}
+
 type Unmounter func(ctx context.Context)
 
 func (fn Unmounter) Unmount(ctx context.Context) {
@@ -252,6 +264,123 @@ func (d *TimeDuration) UnmarshalText(b []byte) error {
 	return fmt.Errorf("invalid duration value: '%s'", b)
 }
 
+// The NewScheduleNext function parses the schedule spec and returns the
+// function to get the next run time.
+//
+// The spec is '@every <duration>', a duration such as '30s',
+// or 5 fields cron 'minute hour day-of-month month day-of-week',
+// the field supports '*', 'a', 'a-b', '*/n', 'a-b/n' and ',' list.
+// If day-of-month and day-of-week are both restricted, match either.
+//
+// The cron spec that never matches such as '0 0 30 2 *' is invalid.
+func NewScheduleNext(spec string) (func(time.Time) time.Time, error) {
+	every := strings.TrimSpace(strings.TrimPrefix(spec, "@every"))
+	dura, err := time.ParseDuration(every)
+	if err == nil {
+		if dura <= 0 {
+			return nil, fmt.Errorf(ErrAppScheduleInvalid, spec)
+		}
+		return func(t time.Time) time.Time { return t.Add(dura) }, nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf(ErrAppScheduleInvalid, spec)
+	}
+	var cron scheduleCron
+	for i, r := range [...][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 6}} {
+		cron.Fields[i], err = parseScheduleField(fields[i], r[0], r[1])
+		if err != nil {
+			return nil, fmt.Errorf(ErrAppScheduleInvalid, spec)
+		}
+	}
+	cron.DayAny = fields[2] == "*" || fields[4] == "*"
+	if cron.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf(ErrAppScheduleInvalid, spec)
+	}
+	return cron.Next, nil
+}
+
+type scheduleCron struct {
+	// minute hour day-of-month month day-of-week bit sets.
+	Fields [5]uint64
+	DayAny bool
+}
+
+// The Next method returns the next matched minute within 8 years to cover
+// leap day, returns zero time if not matched.
+func (cron *scheduleCron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	end := t.AddDate(8, 0, 0)
+	for t.Before(end) {
+		y, m, d := t.Date()
+		switch {
+		case !cron.has(3, int(m)):
+			t = time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location())
+		case !cron.matchDay(t):
+			t = time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+		case !cron.has(1, t.Hour()):
+			t = time.Date(y, m, d, t.Hour()+1, 0, 0, 0, t.Location())
+		case !cron.has(0, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (cron *scheduleCron) has(i, v int) bool {
+	return cron.Fields[i]&(1<<uint(v)) != 0
+}
+
+func (cron *scheduleCron) matchDay(t time.Time) bool {
+	// If day-of-month and day-of-week are restricted, match either.
+	if cron.DayAny {
+		return cron.has(2, t.Day()) && cron.has(4, int(t.Weekday()))
+	}
+	return cron.has(2, t.Day()) || cron.has(4, int(t.Weekday()))
+}
+
+func parseScheduleField(field string, low, high int) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		item, step, ok := strings.Cut(item, "/")
+		inc := 1
+		if ok {
+			var err error
+			inc, err = strconv.Atoi(step)
+			if err != nil || inc <= 0 {
+				return 0, strconv.ErrSyntax
+			}
+		}
+
+		start, end := low, high
+		if item != "*" {
+			a, b, ok := strings.Cut(item, "-")
+			var err error
+			start, err = strconv.Atoi(a)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if ok {
+				end, err = strconv.Atoi(b)
+				if err != nil {
+					return 0, err
+				}
+			}
+		}
+		if start < low || end > high || start > end {
+			return 0, strconv.ErrRange
+		}
+		for i := start; i <= end; i += inc {
+			bits |= 1 << uint(i)
+		}
+	}
+	return bits, nil
+}
+
 type radixData[T any] interface {
 	*T
 	Insert(vals ...any) error
@@ -373,6 +502,51 @@ func NewErrorWithStatus(err error, status int) error {
 	return err
 }
 
+// The RegisterErrorStatus function registers the status of err,
+// [Context].Fatal uses [errors.Is] to match the registered errors
+// when the error does not implement the Status method.
+//
+// Errors registered later are matched first, status 0 deletes the mapping.
+// The error of uncomparable type is not deduplicated or deleted.
+//
+//	eudore.RegisterErrorStatus(sql.ErrNoRows, eudore.StatusNotFound)
+//	eudore.RegisterErrorStatus(context.DeadlineExceeded, eudore.StatusGatewayTimeout)
+func RegisterErrorStatus(err error, status int) {
+	if err == nil {
+		return
+	}
+	errorStatusMutex.Lock()
+	defer errorStatusMutex.Unlock()
+	// the uncomparable error panics when compared with the same type.
+	for i := range errorStatuses {
+		if reflect.TypeOf(err).Comparable() && errorStatuses[i].err == err {
+			errorStatuses = append(errorStatuses[:i], errorStatuses[i+1:]...)
+			break
+		}
+	}
+	if status > 0 {
+		errorStatuses = append(errorStatuses, statusError{err, status})
+	}
+}
+
+var (
+	errorStatuses    []statusError
+	errorStatusMutex sync.RWMutex
+)
+
+// The getRegisterErrorStatus function returns the status
+// registered by [RegisterErrorStatus], or 0 if not matched.
+func getRegisterErrorStatus(err error) int {
+	errorStatusMutex.RLock()
+	defer errorStatusMutex.RUnlock()
+	for i := len(errorStatuses) - 1; i >= 0; i-- {
+		if errors.Is(err, errorStatuses[i].err) {
+			return errorStatuses[i].status
+		}
+	}
+	return 0
+}
+
 type statusError struct {
 	err    error
 	status int
@@ -685,7 +859,7 @@ func GetAnyByStringWithError[T string | bool | time.Time | time.Duration |
 	case string:
 		val = str
 	case bool:
-		val, err = strconv.ParseBool(str)
+		val, err = parseBool(str)
 	case int8:
 		var v int64
 		v, err = strconv.ParseInt(str, 10, 8)
@@ -696,13 +870,13 @@ func GetAnyByStringWithError[T string | bool | time.Time | time.Duration |
 		val = int16(v)
 	case int32:
 		var v int64
-		v, err = strconv.ParseInt(str, 10, 16)
+		v, err = strconv.ParseInt(str, 10, 32)
 		val = int32(v)
 	case int64:
 		val, err = strconv.ParseInt(str, 10, 64)
 	case uint:
 		var v uint64
-		v, err = strconv.ParseUint(str, 10, 32)
+		v, err = strconv.ParseUint(str, 10, 0)
 		val = uint(v)
 	case uint8:
 		var v uint64
@@ -2,7 +2,10 @@ package eudore
 
 import (
 	"encoding"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -174,6 +177,120 @@ func (v *value) getSlice(iValue reflect.Value) (reflect.Value, error) {
 	return v.getValue(iValue.Index(index))
 }
 
+// The GetAnyDiff function compares two objects and returns the paths whose
+// values differ, the value of the path is the value in b.
+//
+// The path uses '.' to join the struct field name, map key and slice index,
+// it can be used by [GetAnyByPath] and [SetAnyByPath].
+// The struct field name uses the [DefaultValueGetSetTags] tag first,
+// unexported fields are skipped.
+//
+// If the map key or slice index only exists in a, the value is nil.
+func GetAnyDiff(a, b any) map[string]any {
+	diff := make(map[string]any)
+	getDiffValue(diff, "", reflect.ValueOf(a), reflect.ValueOf(b))
+	return diff
+}
+
+func getDiffValue(diff map[string]any, path string, a, b reflect.Value) {
+	a, b = getDiffElem(a), getDiffElem(b)
+	switch {
+	case !a.IsValid() && !b.IsValid():
+		return
+	case !a.IsValid() || !b.IsValid() || a.Type() != b.Type():
+		diff[path] = getDiffInterface(b)
+		return
+	}
+
+	_, ok := a.Interface().(encoding.TextMarshaler)
+	switch {
+	case ok:
+	case a.Kind() == reflect.Struct:
+		getDiffStruct(diff, path, a, b)
+		return
+	case a.Kind() == reflect.Map:
+		for _, key := range a.MapKeys() {
+			getDiffValue(diff, getDiffPath(path, fmt.Sprint(key.Interface())),
+				a.MapIndex(key), b.MapIndex(key),
+			)
+		}
+		for _, key := range b.MapKeys() {
+			if !a.MapIndex(key).IsValid() {
+				diff[getDiffPath(path, fmt.Sprint(key.Interface()))] =
+					b.MapIndex(key).Interface()
+			}
+		}
+		return
+	case a.Kind() == reflect.Slice || a.Kind() == reflect.Array:
+		length := b.Len()
+		if a.Len() > length {
+			length = a.Len()
+		}
+		for i := 0; i < length; i++ {
+			var av, bv reflect.Value
+			if i < a.Len() {
+				av = a.Index(i)
+			}
+			if i < b.Len() {
+				bv = b.Index(i)
+			}
+			getDiffValue(diff, getDiffPath(path, strconv.Itoa(i)), av, bv)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		diff[path] = b.Interface()
+	}
+}
+
+func getDiffStruct(diff map[string]any, path string, a, b reflect.Value) {
+	iType := a.Type()
+	for i := 0; i < iType.NumField(); i++ {
+		field := iType.Field(i)
+		if !a.Field(i).CanInterface() {
+			continue
+		}
+		// The anonymous field is matched by GetAnyByPath without name.
+		if field.Anonymous {
+			getDiffValue(diff, path, a.Field(i), b.Field(i))
+			continue
+		}
+
+		name := field.Name
+		for _, tag := range DefaultValueGetSetTags {
+			if val := field.Tag.Get(tag); val != "" {
+				name = val
+				break
+			}
+		}
+		getDiffValue(diff, getDiffPath(path, name), a.Field(i), b.Field(i))
+	}
+}
+
+func getDiffElem(iValue reflect.Value) reflect.Value {
+	for iValue.Kind() == reflect.Ptr || iValue.Kind() == reflect.Interface {
+		if iValue.IsNil() {
+			return reflect.Value{}
+		}
+		iValue = iValue.Elem()
+	}
+	return iValue
+}
+
+func getDiffInterface(iValue reflect.Value) any {
+	if !iValue.IsValid() {
+		return nil
+	}
+	return iValue.Interface()
+}
+
+func getDiffPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
 // The SetAnyByPath function sets the properties of an object, and the object must be a pointer type.
 //
 // The path will be separated using '.', and then the path will be searched for in sequence.
@@ -243,12 +360,116 @@ func SetAnyByPathWithTag(i any, key string, val any, tags []string, all bool) er
 	return v.setValue(iValue)
 }
 
+// The SetFromValues function uses [SetAnyByPathWithTag] to set each key of
+// values as a path of the target, repeated keys are appended to the slice.
+//
+// The target must be a pointer to a struct or map,
+// keys that do not match any field are ignored.
+//
+// If tags is nil, use [DefaultValueGetSetTags].
+func SetFromValues(target any, values url.Values, tags []string) error {
+	if target == nil {
+		return ErrValueInputDataNil
+	}
+	return bindMaps(values, target, tags)
+}
+
+// The MarshalValues function converts the object to [url.Values],
+// which is the reverse of [SetFromValues].
+//
+// The key uses '.' to join the struct field name, map key and slice index,
+// the slice of non-struct values is expanded into repeated keys.
+// The struct field name uses the tags first, unexported fields and
+// nil values are skipped, [encoding.TextMarshaler] is used as the value.
+//
+// If tags is nil, use [DefaultValueGetSetTags].
+func MarshalValues(i any, tags []string) url.Values {
+	if tags == nil {
+		tags = DefaultValueGetSetTags
+	}
+	values := make(url.Values)
+	marshalValues(values, "", reflect.ValueOf(i), tags)
+	return values
+}
+
+func marshalValues(values url.Values, path string, iValue reflect.Value,
+	tags []string,
+) {
+	iValue = getDiffElem(iValue)
+	if !iValue.IsValid() {
+		return
+	}
+	if m, ok := iValue.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err == nil {
+			values.Add(path, string(text))
+		}
+		return
+	}
+
+	switch iValue.Kind() {
+	case reflect.Struct:
+		iType := iValue.Type()
+		for i := 0; i < iType.NumField(); i++ {
+			field := iType.Field(i)
+			if !iValue.Field(i).CanInterface() {
+				continue
+			}
+			if field.Anonymous {
+				marshalValues(values, path, iValue.Field(i), tags)
+				continue
+			}
+			name := field.Name
+			for _, tag := range tags {
+				if val := field.Tag.Get(tag); val != "" {
+					name = val
+					break
+				}
+			}
+			marshalValues(values, getDiffPath(path, name), iValue.Field(i), tags)
+		}
+	case reflect.Map:
+		for _, key := range iValue.MapKeys() {
+			marshalValues(values, getDiffPath(path, fmt.Sprint(key.Interface())),
+				iValue.MapIndex(key), tags,
+			)
+		}
+	case reflect.Slice, reflect.Array:
+		if iValue.Type().Elem().Kind() == reflect.Uint8 {
+			values.Add(path, GetStringByAny(iValue.Interface()))
+			return
+		}
+		for i := 0; i < iValue.Len(); i++ {
+			elem := getDiffElem(iValue.Index(i))
+			if elem.IsValid() {
+				_, ok := elem.Interface().(encoding.TextMarshaler)
+				if ok {
+					marshalValues(values, path, elem, tags)
+					continue
+				}
+			}
+			switch elem.Kind() {
+			case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+				marshalValues(values, getDiffPath(path, strconv.Itoa(i)),
+					elem, tags,
+				)
+			default:
+				marshalValues(values, path, elem, tags)
+			}
+		}
+	default:
+		if path != "" {
+			values.Add(path, GetStringByAny(iValue.Interface()))
+		}
+	}
+}
+
 func (v *value) setValue(iValue reflect.Value) error {
 	if len(v.Keys) == v.Index {
 		err := setValuePtr(reflect.ValueOf(v.Value), iValue)
 		if err != nil {
 			v.Index--
-			err = v.newError("%s", iValue, err)
+			err = v.newError("%w", iValue, err)
 			v.Index++
 		}
 		return err
@@ -456,8 +677,12 @@ func (v *value) newError(f string, iValue reflect.Value, args ...any) error {
 		m = "set"
 	}
 
+	index := v.Index + 1
+	if index > len(v.Keys) {
+		index = len(v.Keys)
+	}
 	err := fmt.Errorf(fmt.Sprintf("%s type %s ", iValue.Kind(), iValue.Type())+f, args...)
-	return fmt.Errorf(ErrFormatValueError, m, strings.Join(v.Keys[:v.Index+1], "."), err)
+	return fmt.Errorf(ErrFormatValueError, m, strings.Join(v.Keys[:index], "."), err)
 }
 
 // 通过字符串获取结构体属性的索引。
@@ -531,6 +756,10 @@ func setValueData(sValue reflect.Value, tValue reflect.Value) error {
 	case sType == tType:
 		tValue.Set(sValue)
 		return nil
+	case sType.Kind() == reflect.String && tType.Kind() == reflect.Slice &&
+		reflect.PointerTo(tType).Implements(typeTextUnmarshal):
+		// net.IP
+		return setValueString(tValue, strings.TrimSpace(sValue.String()))
 	case sType.ConvertibleTo(tType):
 		tValue.Set(sValue.Convert(tType))
 		return nil
@@ -550,7 +779,9 @@ func setValueData(sValue reflect.Value, tValue reflect.Value) error {
 	return fmt.Errorf(ErrFormatValueSetWithValue, sValue.Type().String(), tValue.Type().String())
 }
 
-var bitSizes = [...]int{0, 0, 0, 8, 16, 32, 64, 0, 8, 16, 32, 64, 32, 64, 32, 64}
+// bitSizes defines the bit size of [reflect.Kind] for parsing numbers,
+// the complex type is the bit size of the real part.
+var bitSizes = [...]int{0, 0, 0, 8, 16, 32, 64, 0, 8, 16, 32, 64, 0, 32, 64, 32, 64}
 
 // 使用字符串设置对象的值。
 //
@@ -588,12 +819,17 @@ func setValueString(v reflect.Value, s string) error {
 		}
 		return setValueString(v.Elem(), s)
 	case reflect.Struct:
-		if v.Type().ConvertibleTo(typeTimeTime) {
+		switch {
+		case v.Type() == typeURL:
+			return setURLField(v, s)
+		case v.Type() == typeNetIPNet:
+			return setIPNetField(v, s)
+		case v.Type().ConvertibleTo(typeTimeTime):
 			return setTimeField(v, s)
 		}
-		return fmt.Errorf(ErrFormatValueSetStringUnknownType, v.Kind().String())
+		return setTextField(v, s)
 	default:
-		return fmt.Errorf(ErrFormatValueSetStringUnknownType, v.Kind().String())
+		return setTextField(v, s)
 	}
 
 	if err != nil {
@@ -621,8 +857,9 @@ func setIntField(field reflect.Value, str string) error {
 		if t, err = time.ParseDuration(str); err == nil {
 			field.SetInt(int64(t))
 		}
+		return err
 	}
-	return err
+	return newNumberError(field, str, err)
 }
 
 func setUintField(field reflect.Value, str string) error {
@@ -633,7 +870,7 @@ func setUintField(field reflect.Value, str string) error {
 	if err == nil {
 		field.SetUint(uintVal)
 	}
-	return err
+	return newNumberError(field, str, err)
 }
 
 func setBoolField(field reflect.Value, str string) error {
@@ -641,15 +878,25 @@ func setBoolField(field reflect.Value, str string) error {
 		field.SetBool(true)
 		return nil
 	}
-	boolVal, err := strconv.ParseBool(str)
+	boolVal, err := parseBool(str)
 	if err == nil {
 		field.SetBool(boolVal)
 	}
 	return err
 }
 
+// The parseBool function parses [DefaultValueParseBoolLiterals] and then
+// uses [strconv.ParseBool].
+func parseBool(str string) (bool, error) {
+	val, ok := DefaultValueParseBoolLiterals[strings.ToLower(str)]
+	if ok {
+		return val, nil
+	}
+	return strconv.ParseBool(str)
+}
+
 func setComplexField(field reflect.Value, str string) error {
-	str = strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(str, "("), "i"), ")")
+	str = strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(str, "("), ")"), "i")
 	pos := strings.Index(str, "+")
 	if pos == -1 {
 		pos = len(str)
@@ -658,11 +905,11 @@ func setComplexField(field reflect.Value, str string) error {
 
 	read, err := strconv.ParseFloat(str[:pos], bitSizes[int(field.Kind())])
 	if err != nil {
-		return err
+		return newNumberError(field, str, err)
 	}
 	image, err := strconv.ParseFloat(str[pos+1:], bitSizes[int(field.Kind())])
 	if err != nil {
-		return err
+		return newNumberError(field, str, err)
 	}
 
 	field.SetComplex(complex(read, image))
@@ -677,6 +924,54 @@ func setFloatField(field reflect.Value, str string) error {
 	if err == nil {
 		field.SetFloat(floatVal)
 	}
+	return newNumberError(field, str, err)
+}
+
+// The newNumberError function returns the number parsing error with the
+// field type, and unwraps [strconv.NumError] to [strconv.ErrRange] or
+// [strconv.ErrSyntax].
+func newNumberError(field reflect.Value, str string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	return fmt.Errorf(ErrFormatValueParseNumber, str, field.Type().String(), err)
+}
+
+func setURLField(field reflect.Value, str string) error {
+	u, err := url.Parse(str)
+	if err == nil {
+		field.Set(reflect.ValueOf(*u))
+	}
+	return err
+}
+
+func setIPNetField(field reflect.Value, str string) error {
+	_, n, err := net.ParseCIDR(str)
+	if err == nil {
+		field.Set(reflect.ValueOf(*n))
+	}
+	return err
+}
+
+// The setTextField function uses [encoding.TextUnmarshaler] to set the
+// value of unknown type, such as [net.IP].
+func setTextField(field reflect.Value, str string) error {
+	if !field.IsValid() {
+		return fmt.Errorf(ErrFormatValueSetStringUnknownType, field.Kind().String())
+	}
+	p := reflect.New(field.Type())
+	e, ok := p.Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf(ErrFormatValueSetStringUnknownType, field.Kind().String())
+	}
+	err := e.UnmarshalText([]byte(str))
+	if err == nil {
+		field.Set(p.Elem())
+	}
 	return err
 }
 